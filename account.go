@@ -0,0 +1,95 @@
+package goofx
+
+// AccountKind is the message set an account belongs to.
+type AccountKind string
+
+const (
+	AccountBank       AccountKind = "BANK"
+	AccountCreditCard AccountKind = "CREDITCARD"
+	AccountInvestment AccountKind = "INVESTMENT"
+	AccountLoan       AccountKind = "LOAN"
+)
+
+// Account identifies an account of any kind, independent of the *ACCTFROM aggregate it was
+// read from. Fields that don't apply to the kind of account are left empty.
+type Account struct {
+	Kind      AccountKind
+	BankID    string // Routing number, for bank accounts.
+	BranchID  string
+	BrokerID  string // Broker domain, for investment accounts.
+	AccountID string
+	Type      string // ACCTTYPE of bank accounts or LOANACCTTYPE of loan accounts.
+	Key       string
+	Currency  string // CURDEF of the statement the account was read from, if any.
+}
+
+// key returns the fields identifying the account, ignoring its type and currency.
+func (a Account) key() [4]string {
+	return [4]string{string(a.Kind), a.BankID, a.BrokerID, a.AccountID}
+}
+
+// Account returns the bank account as an Account.
+func (b *BankAccount) Account() Account {
+	return Account{Kind: AccountBank, BankID: b.BankID, BranchID: b.BranchID,
+		AccountID: b.AccountID, Type: b.Type, Key: b.Key}
+}
+
+// Account returns the credit card account as an Account.
+func (c *CreditCardAccount) Account() Account {
+	return Account{Kind: AccountCreditCard, AccountID: c.AccountID, Key: c.Key}
+}
+
+// Account returns the investment account as an Account.
+func (i *InvestmentAccount) Account() Account {
+	return Account{Kind: AccountInvestment, BrokerID: i.BrokerID, AccountID: i.AccountID}
+}
+
+// Account returns the loan account as an Account.
+func (l *LoanAccount) Account() Account {
+	return Account{Kind: AccountLoan, AccountID: l.AccountID, Type: l.Type}
+}
+
+// GetAccount returns the account of the bank statement.
+func (s *StatementResponseSet) GetAccount() Account {
+	return Account{Kind: AccountBank, BankID: s.BankID, BranchID: s.BranchID, AccountID: s.AccountID,
+		Type: s.AccountType, Key: s.AccountKey, Currency: s.Currency}
+}
+
+// GetAccount returns the account of the credit card statement.
+func (s *CreditCardStatementResponseSet) GetAccount() Account {
+	a := s.Account.Account()
+	a.Currency = s.Currency
+	return a
+}
+
+// GetAccount returns the account of the investment statement.
+func (s *InvestmentStatementResponseSet) GetAccount() Account {
+	a := s.Account.Account()
+	a.Currency = s.Currency
+	return a
+}
+
+// GetAccount returns the account of the loan statement.
+func (s *LoanStatementResponseSet) GetAccount() Account {
+	a := s.Account.Account()
+	a.Currency = s.Currency
+	return a
+}
+
+// Accounts returns the accounts of all statements in the document, across message sets, in
+// the order they first appear. Statements without an account id are skipped.
+func (d *Document) Accounts() []Account {
+	var (
+		accounts = make([]Account, 0)
+		seen     = make(map[[4]string]struct{})
+	)
+	for _, s := range d.Statements() {
+		a := s.GetAccount()
+		if _, found := seen[a.key()]; found || a.AccountID == "" {
+			continue
+		}
+		seen[a.key()] = struct{}{}
+		accounts = append(accounts, a)
+	}
+	return accounts
+}
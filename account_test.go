@@ -0,0 +1,151 @@
+package goofx_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("Document.Accounts()", func() {
+		data := "<OFX>" +
+			"<BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STATUS><CODE>0<SEVERITY>INFO</STATUS><STMTRS><CURDEF>USD" +
+			"<BANKACCTFROM><BANKID>456<BRANCHID>12<ACCTID>789<ACCTTYPE>CHECKING<ACCTKEY>K1</BANKACCTFROM>" +
+			"</STMTRS></STMTTRNRS>" +
+			"</BANKMSGSRSV1>" +
+			"<CREDITCARDMSGSRSV1><CCSTMTTRNRS><TRNUID>2<STATUS><CODE>0<SEVERITY>INFO</STATUS><CCSTMTRS>" +
+			"<CURDEF>USD<CCACCTFROM><ACCTID>4111</CCACCTFROM><BANKTRANLIST><DTSTART>20190101<DTEND>20190131" +
+			"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20190115<TRNAMT>-5.00<FITID>c1</STMTTRN></BANKTRANLIST>" +
+			"<LEDGERBAL><BALAMT>-5.00<DTASOF>20190131</LEDGERBAL></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>" +
+			"<INVSTMTMSGSRSV1><INVSTMTTRNRS><TRNUID>3<STATUS><CODE>0<SEVERITY>INFO</STATUS><INVSTMTRS>" +
+			"<DTASOF>20190131<CURDEF>USD<INVACCTFROM><BROKERID>broker.com<ACCTID>X1</INVACCTFROM>" +
+			"<INVTRANLIST><DTSTART>20190101<DTEND>20190131<INVBANKTRAN><STMTTRN><TRNTYPE>CREDIT" +
+			"<DTPOSTED>20190110<TRNAMT>10.00<FITID>i1</STMTTRN><SUBACCTFUND>CASH</INVBANKTRAN></INVTRANLIST>" +
+			"<INVBAL><AVAILCASH>10.00<MARGINBALANCE>0<SHORTBALANCE>0</INVBAL></INVSTMTRS></INVSTMTTRNRS>" +
+			"</INVSTMTMSGSRSV1>" +
+			"<LOANMSGSRSV1><LOANSTMTTRNRS><TRNUID>4<STATUS><CODE>0<SEVERITY>INFO</STATUS><LOANSTMTRS>" +
+			"<CURDEF>USD<LOANACCTFROM><LOANACCTID>L1<LOANACCTTYPE>MORTGAGE</LOANACCTFROM><LOANTRANLIST>" +
+			"<DTSTART>20190101<DTEND>20190131<LOANSTMTTRN><TRNTYPE>PAYMENT<DTPOSTED>20190105<TRNAMT>-1000.00" +
+			"<FITID>l1<LOANTRNAMT><PRINAMT>-600.00<INTAMT>-400.00</LOANTRNAMT></LOANSTMTTRN></LOANTRANLIST>" +
+			"</LOANSTMTRS></LOANSTMTTRNRS></LOANMSGSRSV1>" +
+			"</OFX>"
+
+		It("should list the accounts of every message set", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Err()).To(BeNil())
+			Expect(d.Accounts()).To(Equal([]goofx.Account{
+				{Kind: goofx.AccountBank, BankID: "456", BranchID: "12", AccountID: "789", Type: "CHECKING",
+					Key: "K1", Currency: "USD"},
+				{Kind: goofx.AccountCreditCard, AccountID: "4111", Currency: "USD"},
+				{Kind: goofx.AccountInvestment, BrokerID: "broker.com", AccountID: "X1", Currency: "USD"},
+				{Kind: goofx.AccountLoan, AccountID: "L1", Type: "MORTGAGE", Currency: "USD"},
+			}))
+		})
+		It("should parse the credit card, investment and loan statements", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			cc := d.CCRMS[0].TRS.RS
+			Expect(cc.Transactions).To(HaveLen(1))
+			Expect(cc.LedgerBalance.Amount.String()).To(Equal("-5"))
+			inv := d.IRMS[0].TRS.RS
+			Expect(inv.Transactions.Transactions[0].Transaction.FitID).To(Equal("i1"))
+			Expect(inv.Transactions.Transactions[0].SubAccount).To(Equal("CASH"))
+			Expect(inv.Balance.AvailableCash.String()).To(Equal("10"))
+			loan := d.LRMS[0].TRS.RS
+			Expect(loan.Transactions[0].FitID).To(Equal("l1"))
+			Expect(loan.Transactions[0].Principal.String()).To(Equal("-600"))
+			Expect(loan.Transactions[0].Interest.String()).To(Equal("-400"))
+		})
+		It("should list each account once", func() {
+			r := strings.NewReader("<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKACCTFROM><BANKID>1<ACCTID>2" +
+				"</BANKACCTFROM></STMTRS></STMTTRNRS></BANKMSGSRSV1><BANKMSGSRSV1><STMTTRNRS><STMTRS>" +
+				"<BANKACCTFROM><BANKID>1<ACCTID>2</BANKACCTFROM></STMTRS></STMTTRNRS></BANKMSGSRSV1>" +
+				"<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><CURDEF>USD</CCSTMTRS></CCSTMTTRNRS>" +
+				"</CREDITCARDMSGSRSV1></OFX>")
+			d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Accounts()).To(Equal([]goofx.Account{
+				{Kind: goofx.AccountBank, BankID: "1", AccountID: "2"},
+			}))
+		})
+	})
+	Describe("NewDocumentFromXML() with an SGML investment statement", func() {
+		data := "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\n\n<OFX><INVSTMTMSGSRSV1><INVSTMTTRNRS>" +
+			"<TRNUID>1<STATUS><CODE>0<SEVERITY>INFO</STATUS><INVSTMTRS><DTASOF>20190131<CURDEF>USD" +
+			"<INVACCTFROM><BROKERID>broker.com<ACCTID>X1</INVACCTFROM>" +
+			"<INVTRANLIST><DTSTART>20190101<DTEND>20190131" +
+			"<BUYSTOCK><INVBUY><INVTRAN><FITID>b1<DTTRADE>20190115</INVTRAN>" +
+			"<SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID><UNITS>10<UNITPRICE>150.00" +
+			"<TOTAL>-1500.00<SUBACCTSEC>CASH<SUBACCTFUND>CASH</INVBUY><BUYTYPE>BUY</BUYSTOCK>" +
+			"<INVBANKTRAN><STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20190110<TRNAMT>10.00<FITID>i1</STMTTRN>" +
+			"<SUBACCTFUND>CASH</INVBANKTRAN></INVTRANLIST>" +
+			"<INVPOSLIST><POSSTOCK><INVPOS><SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID>" +
+			"<HELDINACCT>CASH<POSTYPE>LONG<UNITS>10<UNITPRICE>155.00<MKTVAL>1550.00<DTPRICEASOF>20190131" +
+			"</INVPOS></POSSTOCK></INVPOSLIST>" +
+			"<INVBAL><AVAILCASH>10.00<MARGINBALANCE>0<SHORTBALANCE>0</INVBAL>" +
+			"</INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1>" +
+			"<SECLISTMSGSRSV1><SECLIST><STOCKINFO><SECINFO><SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP" +
+			"</SECID><SECNAME>Apple Inc.<TICKER>AAPL</SECINFO><STOCKTYPE>COMMON</STOCKINFO></SECLIST>" +
+			"</SECLISTMSGSRSV1></OFX>"
+
+		It("should keep the period and the security transactions", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Path).To(Equal(goofx.ParsePathCleaned))
+			rs := &d.IRMS[0].TRS.RS
+			start, end := rs.GetPeriod()
+			Expect(start).To(Equal("20190101"))
+			Expect(end).To(Equal("20190131"))
+			Expect(rs.Transactions.Transactions).To(HaveLen(1))
+			Expect(rs.Transactions.Transactions[0].Transaction.FitID).To(Equal("i1"))
+
+			buy, found := rs.Transactions.Extra.Find("BUYSTOCK")
+			Expect(found).To(BeTrue())
+			Expect(buy.Children).To(HaveLen(2))
+			Expect(buy.Children[0].Name()).To(Equal("INVBUY"))
+			trade, found := goofx.Extra(buy.Children[0].Children).Find("INVTRAN")
+			Expect(found).To(BeTrue())
+			fitID, _ := goofx.Extra(trade.Children).Get("FITID")
+			Expect(fitID).To(Equal("b1"))
+			buyType, _ := goofx.Extra(buy.Children).Get("BUYTYPE")
+			Expect(buyType).To(Equal("BUY"))
+		})
+		It("should parse the positions", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			positions := d.IRMS[0].TRS.RS.Positions.Positions
+			Expect(positions).To(HaveLen(1))
+			Expect(positions[0].Kind()).To(Equal("POSSTOCK"))
+			Expect(positions[0].SecurityID).To(Equal("037833100"))
+			Expect(positions[0].SecurityIDType).To(Equal("CUSIP"))
+			Expect(positions[0].Type).To(Equal("LONG"))
+			Expect(positions[0].Units.String()).To(Equal("10"))
+			Expect(positions[0].MarketValue.String()).To(Equal("1550"))
+		})
+		It("should keep the security list", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Diagnostics).To(BeEmpty())
+			list, found := d.Extra.Find("SECLISTMSGSRSV1")
+			Expect(found).To(BeTrue())
+			Expect(list.Children).To(HaveLen(1))
+			stock, found := goofx.Extra(list.Children[0].Children).Find("STOCKINFO")
+			Expect(found).To(BeTrue())
+			info, found := goofx.Extra(stock.Children).Find("SECINFO")
+			Expect(found).To(BeTrue())
+			ticker, _ := goofx.Extra(info.Children).Get("TICKER")
+			Expect(ticker).To(Equal("AAPL"))
+		})
+	})
+	Describe("BankAccount.Account()", func() {
+		It("should convert a BANKACCTTO", func() {
+			b := goofx.BankAccount{BankID: "1", BranchID: "2", AccountID: "3", Type: "SAVINGS", Key: "k"}
+			Expect(b.Account()).To(Equal(goofx.Account{Kind: goofx.AccountBank, BankID: "1", BranchID: "2",
+				AccountID: "3", Type: "SAVINGS", Key: "k"}))
+		})
+	})
+})
@@ -1,10 +1,16 @@
 package goofx
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
 
 var aggregatesMap map[string]struct{}
 var initAggegatesMap sync.Once
 
+var elementsMap map[string]struct{}
+var initElementsMap sync.Once
+
 // GetAggregates returns the singleton aggregates map instance.
 func GetAggregates() map[string]struct{} {
 	initAggegatesMap.Do(func() {
@@ -13,6 +19,18 @@ func GetAggregates() map[string]struct{} {
 			"SIGNONMSGSRSV1", "SONRS", "STATUS", "FI",
 			"BANKMSGSRSV1", "STMTTRNRS", "STMTRS", "BANKACCTFROM",
 			"BANKTRANLIST", "STMTTRN", "LEDGERBAL", "AVAILBAL",
+			"BANKACCTTO", "CCACCTTO", "CURRENCY", "ORIGCURRENCY",
+			"BALLIST", "BAL",
+			"CREDITCARDMSGSRSV1", "CCSTMTTRNRS", "CCSTMTRS", "CCACCTFROM",
+			"INVSTMTMSGSRSV1", "INVSTMTTRNRS", "INVSTMTRS", "INVACCTFROM", "INVTRANLIST",
+			"INVBANKTRAN", "INVPOSLIST", "INVBAL",
+			"BUYDEBT", "BUYMF", "BUYOPT", "BUYOTHER", "BUYSTOCK", "CLOSUREOPT", "INCOME",
+			"INVEXPENSE", "JRNLFUND", "JRNLSEC", "MARGININTEREST", "REINVEST", "RETOFCAP",
+			"SELLDEBT", "SELLMF", "SELLOPT", "SELLOTHER", "SELLSTOCK", "SPLIT", "TRANSFER",
+			"INVBUY", "INVSELL", "INVTRAN", "SECID",
+			"POSDEBT", "POSMF", "POSOPT", "POSOTHER", "POSSTOCK", "INVPOS",
+			"SECLISTMSGSRSV1", "SECLIST", "DEBTINFO", "MFINFO", "OPTINFO", "OTHERINFO", "STOCKINFO",
+			"SECINFO", "MFASSETCLASS", "FIMFASSETCLASS", "PORTION", "FIPORTION",
 		}
 		aggregatesMap = make(map[string]struct{}, len(aggregates))
 		for _, a := range aggregates {
@@ -22,8 +40,75 @@ func GetAggregates() map[string]struct{} {
 	return aggregatesMap
 }
 
+// NormalizeTag returns the canonical spelling of the given tag, without any namespace
+// prefix and in upper case, e.g. ofx:stmttrn becomes STMTTRN.
+func NormalizeTag(tag string) string {
+	if i := strings.LastIndexByte(tag, ':'); i >= 0 {
+		tag = tag[i+1:]
+	}
+	return strings.ToUpper(tag)
+}
+
 // IsAggregate returns true if the given tag is a know aggregate tag.
 func IsAggregate(tag string) bool {
-	_, found := GetAggregates()[tag]
+	_, found := GetAggregates()[NormalizeTag(tag)]
+	return found
+}
+
+// GetElements returns the singleton elements map instance.
+func GetElements() map[string]struct{} {
+	initElementsMap.Do(func() {
+		var elements = []string{
+			"CODE", "SEVERITY", "MESSAGE",
+			"DTSERVER", "USERKEY", "TSKEYEXPIRE", "LANGUAGE", "DTPROFUP", "DTACCTUP",
+			"SESSCOOKIE", "ACCESSKEY", "ORG", "FID",
+			"TRNUID", "CLTCOOKIE", "CURDEF", "MKTGINFO",
+			"BANKID", "BRANCHID", "ACCTID", "ACCTTYPE", "ACCTKEY",
+			"DTSTART", "DTEND",
+			"TRNTYPE", "DTPOSTED", "DTUSER", "DTAVAIL", "TRNAMT", "FITID", "CORRECTFITID",
+			"CORRECTACTION", "SRVRTID", "CHECKNUM", "REFNUM", "SIC", "PAYEEID", "NAME",
+			"EXTDNAME", "PAYEE", "MEMO", "INV401KSOURCE", "CURRATE", "CURSYM",
+			"BALAMT", "DTASOF",
+			"ADDR1", "ADDR2", "ADDR3", "CITY", "STATE", "POSTALCODE", "COUNTRY", "PHONE",
+			"DESC", "BALTYPE", "VALUE",
+			"BROKERID", "SUBACCTFUND", "AVAILCASH", "MARGINBALANCE", "SHORTBALANCE", "BUYPOWER",
+			"DTTRADE", "DTSETTLE", "REVERSALFITID", "UNIQUEID", "UNIQUEIDTYPE", "UNITS", "UNITPRICE",
+			"MARKUP", "MARKDOWN", "COMMISSION", "TAXES", "FEES", "LOAD", "TOTAL", "SUBACCTSEC",
+			"BUYTYPE", "SELLTYPE", "INCOMETYPE", "HELDINACCT", "POSTYPE", "MKTVAL", "DTPRICEASOF",
+			"WITHHOLDING", "TAXEXEMPT", "GAIN", "UNITSSTREET", "UNITSUSER", "REINVDIV", "REINVCG",
+			"SECNAME", "TICKER", "FIID", "RATING", "STOCKTYPE", "MFTYPE", "YIELD", "DTYIELDASOF",
+			"ASSETCLASS", "FIASSETCLASS", "PERCENT", "PARVALUE", "DEBTTYPE", "OPTTYPE", "STRIKEPRICE",
+			"DTEXPIRE", "SHPERCTRCT", "TYPEDESC",
+		}
+		elementsMap = make(map[string]struct{}, len(elements))
+		for _, e := range elements {
+			elementsMap[e] = struct{}{}
+		}
+	})
+	return elementsMap
+}
+
+// IsElement returns true if the given tag is a known element tag.
+func IsElement(tag string) bool {
+	_, found := GetElements()[NormalizeTag(tag)]
 	return found
 }
+
+// dualTags are aggregates per the spec that some institutions send as elements with text
+// instead, e.g. <PAYEE>Name. They are listed as elements and treated as aggregates when they
+// contain other tags.
+var dualTags = map[string]struct{}{
+	"PAYEE": {},
+}
+
+// isDualTag returns true if the given tag is an aggregate that may be sent as an element.
+func isDualTag(tag string) bool {
+	_, found := dualTags[NormalizeTag(tag)]
+	return found
+}
+
+// IsKnownTag returns true if the given tag is a known aggregate or element tag, or an
+// extension tag. Per the OFX spec, extension tags contain a period e.g. INTU.BID.
+func IsKnownTag(tag string) bool {
+	return IsAggregate(tag) || IsElement(tag) || strings.Contains(tag, ".")
+}
@@ -38,6 +38,8 @@ var _ = Describe("goofx", func() {
 				Entry("STMTTRN", "STMTTRN", true),
 				Entry("LEDGERBAL", "LEDGERBAL", true),
 				Entry("AVAILBAL", "AVAILBAL", true),
+				Entry("lowercase", "stmttrn", true),
+				Entry("namespace prefix", "ofx:STMTTRN", true),
 
 				Entry("CODE", "CODE", false),
 				Entry("SEVERITY", "SEVERITY", false),
@@ -45,4 +47,43 @@ var _ = Describe("goofx", func() {
 			)
 		})
 	})
+	Describe("NormalizeTag()", func() {
+		DescribeTable("should return the canonical tag name", func(tag, expected string) {
+			Expect(goofx.NormalizeTag(tag)).To(Equal(expected))
+		},
+			Entry("canonical", "STMTTRN", "STMTTRN"),
+			Entry("lowercase", "stmttrn", "STMTTRN"),
+			Entry("mixed case", "StmtTrn", "STMTTRN"),
+			Entry("namespace prefix", "ofx:stmttrn", "STMTTRN"),
+			Entry("extension", "intu.bid", "INTU.BID"),
+		)
+	})
+	Describe("IsElement()", func() {
+		Context("when given an element name", func() {
+			DescribeTable("should return true if the element is a known element", func(name string, expected bool) {
+				Expect(goofx.IsElement(name)).To(Equal(expected))
+			},
+				Entry("CODE", "CODE", true),
+				Entry("TRNAMT", "TRNAMT", true),
+				Entry("MEMO", "MEMO", true),
+
+				Entry("STMTTRN", "STMTTRN", false),
+				Entry("INTU.BID", "INTU.BID", false),
+				Entry("DEFAULT", "DEFAULT", false),
+			)
+		})
+	})
+	Describe("IsKnownTag()", func() {
+		Context("when given a tag name", func() {
+			DescribeTable("should return true if the tag is known", func(name string, expected bool) {
+				Expect(goofx.IsKnownTag(name)).To(Equal(expected))
+			},
+				Entry("aggregate", "STMTTRN", true),
+				Entry("element", "TRNAMT", true),
+				Entry("extension", "INTU.BID", true),
+
+				Entry("unknown", "savings", false),
+			)
+		})
+	})
 })
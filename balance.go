@@ -0,0 +1,62 @@
+package goofx
+
+import (
+	"strings"
+
+	"github.com/rockstardevs/decimal"
+)
+
+// BalanceType is the type of the value of a BAL record.
+type BalanceType string
+
+const (
+	// BalanceDollar is used for amounts in the currency of the statement, e.g. a credit limit.
+	BalanceDollar BalanceType = "DOLLAR"
+	// BalancePercent is used for rates, e.g. an interest rate.
+	BalancePercent BalanceType = "PERCENT"
+	// BalanceNumber is used for plain numbers, e.g. rewards points.
+	BalanceNumber BalanceType = "NUMBER"
+)
+
+// NamedBalance is a BAL record of a BALLIST, e.g. a credit limit or rewards points.
+type NamedBalance struct {
+	Name        string          `xml:"NAME"`
+	Description string          `xml:"DESC"`
+	Type        BalanceType     `xml:"BALTYPE"`
+	Value       decimal.Decimal `xml:"VALUE"`
+	Date        string          `xml:"DTASOF,omitempty"`
+	Currency    *Currency       `xml:"CURRENCY,omitempty"`
+	Extra       Extra           `xml:",any"`
+}
+
+// BalanceList is the list of BAL records of a BALLIST.
+type BalanceList []NamedBalance
+
+// Find returns the first balance with the given name, compared case-insensitively.
+func (l BalanceList) Find(name string) (*NamedBalance, bool) {
+	for i := range l {
+		if strings.EqualFold(l[i].Name, name) {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// Value returns the value of the first balance with the given name.
+func (l BalanceList) Value(name string) (decimal.Decimal, bool) {
+	if b, found := l.Find(name); found {
+		return b.Value, true
+	}
+	return decimal.Decimal{}, false
+}
+
+// OfType returns the balances of the given type, in order.
+func (l BalanceList) OfType(t BalanceType) BalanceList {
+	var balances BalanceList
+	for _, b := range l {
+		if b.Type == t {
+			balances = append(balances, b)
+		}
+	}
+	return balances
+}
@@ -0,0 +1,54 @@
+package goofx_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("NewDocumentFromXML() with a balance list", func() {
+		data := "<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD<LEDGERBAL><BALAMT>-500.00<DTASOF>20190131</LEDGERBAL>" +
+			"<BALLIST>" +
+			"<BAL><NAME>Credit Limit<DESC>Total credit limit<BALTYPE>DOLLAR<VALUE>5000.00<DTASOF>20190131</BAL>" +
+			"<BAL><NAME>Minimum Payment Due<DESC>Minimum due<BALTYPE>DOLLAR<VALUE>25.00" +
+			"<CURRENCY><CURRATE>1.0<CURSYM>USD</CURRENCY></BAL>" +
+			"<BAL><NAME>Rewards<DESC>Points<BALTYPE>NUMBER<VALUE>1234</BAL>" +
+			"</BALLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+		var balances goofx.BalanceList
+		BeforeEach(func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			balances = d.BRMS[0].TRS.RS.Balances
+		})
+
+		It("should parse the balances in order", func() {
+			Expect(balances).To(HaveLen(3))
+			Expect(balances[0].Name).To(Equal("Credit Limit"))
+			Expect(balances[0].Description).To(Equal("Total credit limit"))
+			Expect(balances[0].Type).To(Equal(goofx.BalanceDollar))
+			Expect(balances[0].Value.String()).To(Equal("5000"))
+			Expect(balances[0].Date).To(Equal("20190131"))
+			Expect(balances[1].Currency.Symbol).To(Equal("USD"))
+			Expect(balances[2].Type).To(Equal(goofx.BalanceNumber))
+		})
+		It("should find balances by name", func() {
+			b, found := balances.Find("minimum payment due")
+			Expect(found).To(BeTrue())
+			Expect(b.Value.String()).To(Equal("25"))
+			_, found = balances.Find("Cash Advance Limit")
+			Expect(found).To(BeFalse())
+
+			value, found := balances.Value("Rewards")
+			Expect(found).To(BeTrue())
+			Expect(value.String()).To(Equal("1234"))
+		})
+		It("should filter balances by type", func() {
+			Expect(balances.OfType(goofx.BalanceDollar)).To(HaveLen(2))
+			Expect(balances.OfType(goofx.BalancePercent)).To(BeEmpty())
+		})
+	})
+})
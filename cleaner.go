@@ -2,9 +2,13 @@ package goofx
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"html"
 	"io"
+	"regexp"
 	"strings"
 
 	"github.com/golang/glog"
@@ -18,42 +22,291 @@ type Cleaner interface {
 	CleanupXML() (*bytes.Buffer, error)
 }
 
+// ContextCleaner is a Cleaner that can be cancelled while cleaning.
+type ContextCleaner interface {
+	Cleaner
+	// CleanupXMLContext is like CleanupXML but returns ctx.Err() once the context is done.
+	CleanupXMLContext(context.Context) (*bytes.Buffer, error)
+}
+
+// ofxStartPattern matches the OFX start tag in any case and with any namespace prefix.
+var ofxStartPattern = regexp.MustCompile(`(?i)<(?:[\w.-]+:)?OFX[\s>]`)
+
 type cleaner struct {
 	decoder     *xml.Decoder
 	tagStack    TagStack
+	limits      Limits            // Resource limits applied while cleaning.
+	version     string            // Spec version overriding the VERSION header, if set.
+	schema      *Schema           // Tags defined by the spec version of the data.
+	diagnostics diagnostics       // Repairs made while cleaning.
+	txnCount    int               // Number of transactions seen so far.
 	lastData    string            // Holds the last parsed char data.
 	lastElement *xml.StartElement // Last parsed element start tag.
 	cleanXML    bytes.Buffer      // Buffer to hold cleaned XML.
+
+	orphanPolicy   OrphanPolicy      // What to do with char data without an enclosing element.
+	fragments      []string          // Char data collected by OrphanCollect.
+	prevElementEnd int               // Offset of the end tag of the last written element, 0 if none.
+	handler        Handler           // Receives the events of the cleaned data, if set.
+	pending        *xml.StartElement // Element whose event is held back for OrphanAttach, if any.
+	pendingData    string            // Data of the pending element.
+	closeAtEnd     bool              // Close aggregates still open at the end of the data.
+}
+
+// OrphanPolicy decides what the cleaner does with char data that has no enclosing element,
+// e.g. footers or page-break artifacts between aggregates.
+type OrphanPolicy int
+
+const (
+	// OrphanError fails cleaning, this is the default.
+	OrphanError OrphanPolicy = iota
+	// OrphanDrop discards the char data.
+	OrphanDrop
+	// OrphanAttach appends the char data to the data of the previous element.
+	OrphanAttach
+	// OrphanCollect collects the char data into Document.Fragments.
+	OrphanCollect
+)
+
+// errOrphanData is returned by orphanTokenReader for char data without an enclosing element.
+var errOrphanData = errors.New("error - char data without an enclosing element")
+
+// orphanTokenReader is an xml.TokenReader failing on char data next to the children of an
+// aggregate, e.g. a footer in well-formed XML. Such data is left to the cleaner to handle
+// as per its OrphanPolicy.
+type orphanTokenReader struct {
+	reader   xml.TokenReader
+	children []bool    // Whether each open element has had a child element so far.
+	next     xml.Token // Token read ahead of char data, returned by the next call.
+}
+
+// Token returns the next token from the underlying reader, or errOrphanData.
+func (r *orphanTokenReader) Token() (xml.Token, error) {
+	token := r.next
+	r.next = nil
+	if token == nil {
+		var err error
+		if token, err = r.reader.Token(); err != nil {
+			return nil, err
+		}
+	}
+	switch t := token.(type) {
+	case xml.StartElement:
+		if n := len(r.children); n > 0 {
+			r.children[n-1] = true
+		}
+		r.children = append(r.children, false)
+	case xml.EndElement:
+		if n := len(r.children); n > 0 {
+			r.children = r.children[:n-1]
+		}
+	case xml.CharData:
+		n := len(r.children)
+		if n == 0 || len(bytes.TrimSpace(t)) == 0 {
+			break
+		}
+		if r.children[n-1] {
+			return nil, errOrphanData
+		}
+		// Data followed by a child element has no enclosing element either.
+		token = t.Copy()
+		next, err := r.reader.Token()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := next.(xml.StartElement); ok {
+			return nil, errOrphanData
+		}
+		r.next = next
+	}
+	return token, nil
+}
+
+// FragmentCollector is implemented by cleaners that collect char data without an enclosing element.
+type FragmentCollector interface {
+	Fragments() []string
+}
+
+// CleanerOption configures a cleaner returned by NewCleaner.
+type CleanerOption func(*cleaner)
+
+// WithLimits sets the resource limits enforced by the cleaner.
+func WithLimits(limits Limits) CleanerOption {
+	return func(c *cleaner) {
+		c.limits = limits
+	}
+}
+
+// WithVersion sets the spec version used to infer tags e.g. 102, instead of the VERSION
+// header of the data.
+func WithVersion(version string) CleanerOption {
+	return func(c *cleaner) {
+		c.version = version
+	}
+}
+
+// WithOrphanPolicy sets what the cleaner does with char data that has no enclosing element.
+func WithOrphanPolicy(policy OrphanPolicy) CleanerOption {
+	return func(c *cleaner) {
+		c.orphanPolicy = policy
+	}
 }
 
 // NewCleaner returns an instance of cleaner.
-func NewCleaner() Cleaner {
-	return &cleaner{tagStack: NewStack()}
+func NewCleaner(opts ...CleanerOption) Cleaner {
+	c := &cleaner{tagStack: NewStack()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// Init initializes this cleaner with the given data.
+// WithOptions returns a new cleaner with the settings of this cleaner and the given options
+// applied, leaving this cleaner unchanged.
+func (c *cleaner) WithOptions(opts ...CleanerOption) Cleaner {
+	return NewCleaner(append([]CleanerOption{func(clone *cleaner) {
+		clone.limits = c.limits
+		clone.version = c.version
+		clone.orphanPolicy = c.orphanPolicy
+		clone.handler = c.handler
+		clone.closeAtEnd = c.closeAtEnd
+	}}, opts...)...)
+}
+
+// withCloseAtEnd makes the cleaner close the last element and any aggregates still open at
+// the end of the data instead of failing, e.g. for documents split by DocumentScanner.
+func withCloseAtEnd() CleanerOption {
+	return func(c *cleaner) {
+		c.closeAtEnd = true
+	}
+}
+
+// Limits returns the resource limits enforced by this cleaner.
+func (c *cleaner) Limits() Limits {
+	return c.limits
+}
+
+// Diagnostics returns the repairs made by this cleaner.
+func (c *cleaner) Diagnostics() []Diagnostic {
+	return c.diagnostics.items
+}
+
+// Fragments returns the char data collected by OrphanCollect.
+func (c *cleaner) Fragments() []string {
+	return c.fragments
+}
+
+// Init initializes this cleaner with the given UTF-8 or UTF-16 data. Limits.MaxBytes is not
+// checked here, it applies to the raw input as it is read, before any preprocessing.
 func (c *cleaner) Init(data []byte) error {
+	data = toUTF8(data)
 	// Detect the start of XML like data.
-	xmlIndex := bytes.Index(data, []byte("<OFX>"))
+	xmlIndex := findOFXStart(data)
 	if xmlIndex == -1 {
 		return fmt.Errorf("error - invalid file, OFX tag not found")
 	}
 
+	version := c.version
+	if version == "" {
+		version = ParseHeader(data).Version
+	}
+	c.schema = SchemaForVersion(version)
+	c.diagnostics.schema = c.schema
+
 	// Start a xml decoder on the context of source data that is XML like.
-	reader := bytes.NewReader(data[xmlIndex:])
+	reader := bytes.NewReader(escapeStrayMarkup(data[xmlIndex:], c.schema))
 	c.decoder = xml.NewDecoder(reader)
+	// Bank exports often contain HTML named entities and stray ampersands in char data.
+	// Resolve the former and pass the latter through as literal text to be escaped later.
+	c.decoder.Strict = false
+	c.decoder.Entity = xml.HTMLEntity
 
 	return nil
 }
 
-func (c *cleaner) closeLastElement(t *xml.EndElement) {
+// findOFXStart returns the index of the OFX start tag in the given data, or -1 if not found.
+func findOFXStart(data []byte) int {
+	loc := ofxStartPattern.FindIndex(data)
+	if loc == nil {
+		return -1
+	}
+	return loc[0]
+}
+
+func (c *cleaner) closeLastElement(t *xml.EndElement) error {
+	start := c.lastElement
 	if t != nil {
-		writeElementFromName(t.Name, c.lastData, &c.cleanXML)
-	} else {
-		writeElement(c.lastElement, c.lastData, &c.cleanXML)
+		start = &xml.StartElement{Name: t.Name}
 	}
+	data := c.lastData
 	c.lastData = ""
 	c.lastElement = nil
+	// With a handler the cleaned XML isn't kept, only the events are sent.
+	if c.handler != nil {
+		if err := c.flushElement(); err != nil {
+			return err
+		}
+		if c.orphanPolicy == OrphanAttach {
+			// Held back till the next event, as OrphanAttach may still add to the value.
+			c.pending, c.pendingData = start, data
+			return nil
+		}
+		return c.handler.Element(start.Name.Local, html.UnescapeString(data))
+	}
+	writeElement(start, data, &c.cleanXML)
+	// Remember where the end tag just written starts, for OrphanAttach.
+	c.prevElementEnd = c.cleanXML.Len() - len("</>") - len(start.Name.Local)
+	return nil
+}
+
+// flushElement sends the element event held back for OrphanAttach, if any.
+func (c *cleaner) flushElement() error {
+	if c.pending == nil {
+		return nil
+	}
+	name, data := c.pending.Name.Local, c.pendingData
+	c.pending, c.pendingData = nil, ""
+	return c.handler.Element(name, html.UnescapeString(data))
+}
+
+// canAttach returns true if OrphanAttach has a previous element to attach data to. With a
+// handler, that is an element whose event is still held back.
+func (c *cleaner) canAttach() bool {
+	if c.handler != nil {
+		return c.pending != nil
+	}
+	return c.prevElementEnd != 0
+}
+
+// handleOrphanData handles last data that has no enclosing element as per the orphan policy.
+func (c *cleaner) handleOrphanData() error {
+	switch {
+	case c.orphanPolicy == OrphanDrop || (c.orphanPolicy == OrphanAttach && !c.canAttach()):
+		c.diagnostics.add(DiagnosticOrphanData, "", "", "charData(%s) missing start and end tags, dropped", c.lastData)
+	case c.orphanPolicy == OrphanAttach && c.handler != nil:
+		c.pendingData += " " + c.lastData
+		c.diagnostics.add(DiagnosticOrphanData, "", "", "charData(%s) missing start and end tags, attached to previous element", c.lastData)
+	case c.orphanPolicy == OrphanAttach:
+		// Insert the data before the end tag of the previous element, which may no longer be
+		// at the end of the buffer if aggregate tags were written since.
+		insert := " " + c.lastData
+		cleanXML := c.cleanXML.Bytes()
+		var result bytes.Buffer
+		result.Grow(len(cleanXML) + len(insert))
+		result.Write(cleanXML[:c.prevElementEnd])
+		result.WriteString(insert)
+		result.Write(cleanXML[c.prevElementEnd:])
+		c.cleanXML = result
+		c.prevElementEnd += len(insert)
+		c.diagnostics.add(DiagnosticOrphanData, "", "", "charData(%s) missing start and end tags, attached to previous element", c.lastData)
+	case c.orphanPolicy == OrphanCollect:
+		c.fragments = append(c.fragments, html.UnescapeString(c.lastData))
+		c.diagnostics.add(DiagnosticOrphanData, "", "", "charData(%s) missing start and end tags, collected", c.lastData)
+	default:
+		return fmt.Errorf("error: charData(%s) missing start and end tags", c.lastData)
+	}
+	c.lastData = ""
+	return nil
 }
 
 func (c *cleaner) processStartElement(t xml.StartElement) error {
@@ -65,16 +318,27 @@ func (c *cleaner) processStartElement(t xml.StartElement) error {
 		// If last data exists but no last element, the current tag being a start element
 		// implies the data is missing both start and end tags.
 		if c.lastElement == nil {
-			return fmt.Errorf("error: charData(%s) missing start and end tags", c.lastData)
+			if err := c.handleOrphanData(); err != nil {
+				return err
+			}
+		} else if err := c.closeLastElement(nil); err != nil {
+			return err
 		}
-		c.closeLastElement(nil)
+	}
+	// A dual tag directly followed by a start tag is an aggregate, e.g. <PAYEE><NAME>.
+	if c.lastElement != nil && c.lastData == "" && isDualTag(c.lastElement.Name.Local) {
+		glog.V(3).Infof("StartTag: dual tag %s is aggregate", c.lastElement.Name.Local)
+		if err := c.startAggregate(*c.lastElement); err != nil {
+			return err
+		}
+		c.lastElement = nil
 	}
 	// If this tag is an aggregate, flush it and push it on the stack for dequeue later.
 	// If this tag is an element, update lastElement as it can't have nested tags.
-	if IsAggregate(t.Name.Local) {
-		glog.V(3).Infof("StartTag: %s is aggregate, pushing to stack", t.Name.Local)
-		c.tagStack.Push(&t)
-		writeStartTag(&t, &c.cleanXML)
+	if c.schema.IsAggregate(t.Name.Local) {
+		if err := c.startAggregate(t); err != nil {
+			return err
+		}
 	} else {
 		glog.V(3).Infof("StartTag: %s is NOT aggregate, updating lastElement", t.Name.Local)
 		c.lastElement = &t
@@ -84,9 +348,46 @@ func (c *cleaner) processStartElement(t xml.StartElement) error {
 	return nil
 }
 
+// startAggregate writes the start tag of the given aggregate and pushes it on the stack.
+func (c *cleaner) startAggregate(t xml.StartElement) error {
+	glog.V(3).Infof("StartTag: %s is aggregate, pushing to stack", t.Name.Local)
+	if c.limits.MaxDepth > 0 && c.tagStack.Size() >= c.limits.MaxDepth {
+		return ErrNestingTooDeep
+	}
+	if t.Name.Local == "STMTTRN" {
+		c.txnCount++
+		if c.limits.MaxTransactions > 0 && c.txnCount > c.limits.MaxTransactions {
+			return ErrTooManyTransactions
+		}
+	}
+	c.tagStack.Push(&t)
+	if c.handler != nil {
+		if err := c.flushElement(); err != nil {
+			return err
+		}
+		return c.handler.StartAggregate(t.Name.Local)
+	}
+	writeStartTag(&t, &c.cleanXML)
+	return nil
+}
+
+// isOpenAggregate returns true if an aggregate with the given name is on the stack.
+func (c *cleaner) isOpenAggregate(name string) bool {
+	for _, open := range c.tagStack.Dump() {
+		if open == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *cleaner) processEndElement(t xml.EndElement) error {
 	glog.V(3).Infof("case end element %s", t.Name.Local)
-	isAggregate := IsAggregate(t.Name.Local)
+	isAggregate := c.schema.IsAggregate(t.Name.Local)
+	// A dual tag is an aggregate if it was opened as one, and isn't the element being closed.
+	if isDualTag(t.Name.Local) && (c.lastElement == nil || c.lastElement.Name != t.Name) {
+		isAggregate = c.isOpenAggregate(t.Name.Local)
+	}
 	// If last data exists, it takes highest precedence. This is an end tag and last data
 	// exists implies this must be the corresponding end tag if this is an element.
 	// If this is an aggregate, the previous element end tag is missing.
@@ -95,19 +396,33 @@ func (c *cleaner) processEndElement(t xml.EndElement) error {
 		// If this is an element tag but not the same as lastElement, that is an error.
 		if c.lastElement != nil && t.Name != c.lastElement.Name && !isAggregate {
 			// There is a last element as well this is a data (non aggregate) element.
-			// We can not determine which of the two is missing a closing tag.
-			return fmt.Errorf("error: charData(%s) has ambigious closing tags", c.lastData)
+			// Decide which of the two the data belongs to and close that element.
+			name := resolveCloseTag(c.schema, c.lastElement.Name.Local, t.Name.Local)
+			// The original is the spelling that was repaired, the tag that lost.
+			original := t.Name.Local
+			if name == t.Name.Local {
+				original = c.lastElement.Name.Local
+			}
+			c.diagnostics.add(DiagnosticMismatchedClose, name, original,
+				"charData(%s) opened by <%s> and closed by </%s>, closed as </%s>",
+				c.lastData, c.lastElement.Name.Local, t.Name.Local, name)
+			return c.closeLastElement(&xml.EndElement{Name: xml.Name{Local: name}})
 		}
-		// If this is an aggregate tag and lastElement isn't set, that is an error.
+		// If this is an aggregate tag and lastElement isn't set, the data has no enclosing element.
 		if c.lastElement == nil && isAggregate {
-			return fmt.Errorf("error: charData(%s) missing start and end tags", c.lastData)
-		}
-		if c.lastElement != nil {
+			if err := c.handleOrphanData(); err != nil {
+				return err
+			}
+		} else if c.lastElement != nil {
 			// Implies this tag is aggregate or same as lastElement.
-			c.closeLastElement(nil)
+			if err := c.closeLastElement(nil); err != nil {
+				return err
+			}
 		} else {
 			// Implies this tag is not aggregate.
-			c.closeLastElement(&t)
+			if err := c.closeLastElement(&t); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -117,7 +432,9 @@ func (c *cleaner) processEndElement(t xml.EndElement) error {
 		// Close every open tag till the current closing tag is matched.
 		for !c.tagStack.IsEmpty() {
 			lastTag, _ := c.tagStack.Pop()
-			writeEndTag(lastTag.Name, &c.cleanXML)
+			if err := c.endAggregate(lastTag.Name); err != nil {
+				return err
+			}
 			if lastTag.Name.Local == t.Name.Local {
 				break
 			}
@@ -128,14 +445,86 @@ func (c *cleaner) processEndElement(t xml.EndElement) error {
 	return nil
 }
 
+// closeAll handles the end of the data. Aggregates still open are an error unless closeAtEnd
+// is set, in which case they are closed along with the last element.
+func (c *cleaner) closeAll() error {
+	if c.lastData != "" && c.lastElement == nil && c.orphanPolicy != OrphanError {
+		// Trailing data after the last tag e.g. a footer.
+		if err := c.handleOrphanData(); err != nil {
+			return err
+		}
+	}
+	if !c.closeAtEnd {
+		if open := c.tagStack.Dump(); len(open) > 0 {
+			return fmt.Errorf("error - aggregate %s not closed at end of data", open[len(open)-1])
+		}
+		return nil
+	}
+	if c.lastData != "" && c.lastElement != nil {
+		if err := c.closeLastElement(nil); err != nil {
+			return err
+		}
+	}
+	for !c.tagStack.IsEmpty() {
+		lastTag, _ := c.tagStack.Pop()
+		if err := c.endAggregate(lastTag.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endAggregate writes the end tag of the given aggregate.
+func (c *cleaner) endAggregate(name xml.Name) error {
+	if c.handler != nil {
+		if err := c.flushElement(); err != nil {
+			return err
+		}
+		return c.handler.EndAggregate(name.Local)
+	}
+	writeEndTag(name, &c.cleanXML)
+	return nil
+}
+
+// maxEditDistance is the largest edit distance between two tag names for one to be
+// considered a misspelling of the other.
+const maxEditDistance = 2
+
+// resolveCloseTag decides which element owns the data between the given start tag and a
+// mismatched end tag. The start tag wins, unless it is not a known element and is within
+// maxEditDistance of the end tag which is, i.e. the start tag is a misspelling.
+func resolveCloseTag(schema *Schema, start, end string) string {
+	if !schema.IsElement(start) && schema.IsElement(end) && editDistance(start, end) <= maxEditDistance {
+		return end
+	}
+	return start
+}
+
 // CleanupXML returns cleaned XML from the given data.
 func (c *cleaner) CleanupXML() (*bytes.Buffer, error) {
+	return c.CleanupXMLContext(context.Background())
+}
+
+// CleanupXMLContext returns cleaned XML from the given data, checking the given context
+// for cancellation periodically.
+func (c *cleaner) CleanupXMLContext(ctx context.Context) (*bytes.Buffer, error) {
 	// Read parsed XML tokens from the XML decoder into token and re-assemble them into another
 	// buffer, while adding any missing starting or closing tags and trimming spaces/newlines.
-	for {
+	for count := 1; ; count++ {
+		if count%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		token, err := c.decoder.RawToken()
 		if err != nil {
 			if err == io.EOF {
+				if err := c.closeAll(); err != nil {
+					return nil, err
+				}
+				if err := c.flushElement(); err != nil {
+					return nil, err
+				}
 				break
 			}
 			return nil, err
@@ -143,13 +532,19 @@ func (c *cleaner) CleanupXML() (*bytes.Buffer, error) {
 
 		switch t := token.(type) {
 		case xml.CharData:
-			c.lastData = EscapeString(strings.TrimSpace(string([]byte(t))))
+			data := strings.TrimSpace(string([]byte(t)))
+			if c.limits.MaxDataLength > 0 && len(data) > c.limits.MaxDataLength {
+				return nil, ErrDataTooLong
+			}
+			c.lastData = EscapeString(data)
 			glog.V(3).Infof("case chardata (%s) %#v", c.lastData, t)
 		case xml.StartElement:
+			t.Name = c.diagnostics.normalizeName(t.Name)
 			if err := c.processStartElement(t); err != nil {
 				return nil, err
 			}
 		case xml.EndElement:
+			t.Name = c.diagnostics.normalizeName(t.Name)
 			if err := c.processEndElement(t); err != nil {
 				return nil, err
 			}
@@ -1,6 +1,9 @@
 package goofx_test
 
 import (
+	"context"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -45,12 +48,12 @@ var _ = Describe("goofx", func() {
 				Entry("when elements are missing start and end tag",
 					[]byte(`<OFX><STMTTRN>foo</STMTTRN></STATUS>`),
 					"error: charData(foo) missing start and end tags"),
-				Entry("when elements have mismatched start and end tag",
-					[]byte(`<OFX><CODE>bar</SEVERITY></STATUS>`),
-					"error: charData(bar) has ambigious closing tags"),
 				Entry("when elements have mismatched start and end tag",
 					[]byte(`<OFX><STATUS>baz<SEVERITY>INFO</STATUS>`),
 					"error: charData(baz) missing start and end tags"),
+				Entry("when the document is missing its end tags",
+					[]byte(`<OFX><STMTTRN><NAME>Foo`),
+					"error - aggregate STMTTRN not closed at end of data"),
 			)
 		})
 		Context("when given a parsable OFX document", func() {
@@ -93,7 +96,198 @@ var _ = Describe("goofx", func() {
 				Entry("when aggregates have no nested elements",
 					[]byte(`<OFX><BANKMSGSRSV1></STMTTRNRS></BANKMSGSRSV1></OFX>`),
 					[]byte(`<OFX><BANKMSGSRSV1></BANKMSGSRSV1></OFX>`)),
+				Entry("when elements have mismatched start and end tag",
+					[]byte(`<OFX><CODE>bar</SEVERITY></STATUS>`),
+					[]byte(`<OFX><CODE>bar</CODE></OFX>`)),
+				Entry("when element end tag is misspelled",
+					[]byte(`<OFX><STMTTRN><NAME>Foo</NAME1><MEMO>x</NAME></STMTTRN></OFX>`),
+					[]byte(`<OFX><STMTTRN><NAME>Foo</NAME><MEMO>x</MEMO></STMTTRN></OFX>`)),
+				Entry("when element start tag is misspelled",
+					[]byte(`<OFX><STMTTRN><NAEM>Foo</NAME></STMTTRN></OFX>`),
+					[]byte(`<OFX><STMTTRN><NAME>Foo</NAME></STMTTRN></OFX>`)),
+				Entry("when element data contains an unknown tag",
+					[]byte(`<OFX><MEMO>Transfer <savings><NAME>x</OFX>`),
+					[]byte(`<OFX><MEMO>Transfer &lt;savings&gt;</MEMO><NAME>x</NAME></OFX>`)),
+				Entry("when element data contains an unknown tag followed by data",
+					[]byte(`<OFX><NAME>A<B> LLC<MEMO>x</OFX>`),
+					[]byte(`<OFX><NAME>A&lt;B&gt; LLC</NAME><MEMO>x</MEMO></OFX>`)),
+				Entry("when element data contains a '<' that can not start a tag",
+					[]byte(`<OFX><MEMO>1 < 2 <3</OFX>`),
+					[]byte(`<OFX><MEMO>1 &lt; 2 &lt;3</MEMO></OFX>`)),
+				Entry("when element data contains an incomplete tag",
+					[]byte(`<OFX><NAME>A<B LLC<MEMO>x</OFX>`),
+					[]byte(`<OFX><NAME>A&lt;B LLC</NAME><MEMO>x</MEMO></OFX>`)),
+				Entry("when element data is followed by an extension tag",
+					[]byte(`<OFX><NAME>Foo<INTU.BID>1</OFX>`),
+					[]byte(`<OFX><NAME>Foo</NAME><INTU.BID>1</INTU.BID></OFX>`)),
+				Entry("when an unknown tag is not inside element data",
+					[]byte(`<OFX><STATUS><FOO>1</STATUS></OFX>`),
+					[]byte(`<OFX><STATUS><FOO>1</FOO></STATUS></OFX>`)),
+				Entry("when tags are lowercase or namespace prefixed",
+					[]byte(`<ofx><ofx:StmtTrn><trnamt>1.00<ofx:Memo>x</ofx:stmttrn></ofx>`),
+					[]byte(`<OFX><STMTTRN><TRNAMT>1.00</TRNAMT><MEMO>x</MEMO></STMTTRN></OFX>`)),
+				Entry("when element data contains HTML named entities",
+					[]byte(`<OFX><NAME>Caf&eacute;&nbsp;Bar&reg;</OFX>`),
+					[]byte("<OFX><NAME>Caf\u00e9\u00a0Bar\u00ae</NAME></OFX>")),
+				Entry("when element data contains numeric character references",
+					[]byte(`<OFX><NAME>Caf&#233; &#x41;</OFX>`),
+					[]byte("<OFX><NAME>Caf\u00e9 A</NAME></OFX>")),
+				Entry("when element data contains stray ampersands",
+					[]byte(`<OFX><NAME>AT&T & Sons&co;</OFX>`),
+					[]byte(`<OFX><NAME>AT&amp;T &amp; Sons&amp;co;</NAME></OFX>`)),
 			)
 		})
+		Context("when given an OFX document exceeding the limits", func() {
+			DescribeTable("should return an error", func(data []byte, limits goofx.Limits, expected error) {
+				cleaner := goofx.NewCleaner(goofx.WithLimits(limits))
+				err := cleaner.Init(data)
+				Expect(err).To(BeNil())
+				cleanData, err := cleaner.CleanupXML()
+				Expect(cleanData).To(BeNil())
+				Expect(err).To(MatchError(expected))
+			},
+				Entry("when aggregates are nested too deep",
+					[]byte(`<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`),
+					goofx.Limits{MaxDepth: 3},
+					goofx.ErrNestingTooDeep),
+				Entry("when element data is too long",
+					[]byte(`<OFX><STMTTRN><NAME>Sample Expense</STMTTRN></OFX>`),
+					goofx.Limits{MaxDataLength: 8},
+					goofx.ErrDataTooLong),
+				Entry("when there are too many transactions",
+					[]byte(`<OFX><STMTTRN><FITID>1</STMTTRN><STMTTRN><FITID>2</STMTTRN></OFX>`),
+					goofx.Limits{MaxTransactions: 1},
+					goofx.ErrTooManyTransactions),
+			)
+		})
+		Context("when given an OFX document within the limits", func() {
+			It("should parse to clean XML", func() {
+				limits := goofx.Limits{MaxBytes: 128, MaxDepth: 2, MaxDataLength: 8, MaxTransactions: 1}
+				cleaner := goofx.NewCleaner(goofx.WithLimits(limits))
+				err := cleaner.Init([]byte(`<OFX><STMTTRN><FITID>1</STMTTRN></OFX>`))
+				Expect(err).To(BeNil())
+				cleanData, err := cleaner.CleanupXML()
+				Expect(err).To(BeNil())
+				Expect(cleanData.String()).To(Equal(`<OFX><STMTTRN><FITID>1</FITID></STMTTRN></OFX>`))
+			})
+		})
+	})
+	Describe("CleanupXML() with an orphan policy", func() {
+		DescribeTable("should handle char data without an enclosing element", func(policy goofx.OrphanPolicy, expected string, fragments []string) {
+			cleaner := goofx.NewCleaner(goofx.WithOrphanPolicy(policy))
+			err := cleaner.Init([]byte(`<OFX><STMTTRN><NAME>Foo</NAME></STMTTRN>Page 1 of 2<STMTTRN><MEMO>x</STMTTRN></OFX>`))
+			Expect(err).To(BeNil())
+			cleanData, err := cleaner.CleanupXML()
+			Expect(err).To(BeNil())
+			Expect(cleanData.String()).To(Equal(expected))
+			Expect(cleaner.(goofx.FragmentCollector).Fragments()).To(Equal(fragments))
+		},
+			Entry("when dropping",
+				goofx.OrphanDrop,
+				`<OFX><STMTTRN><NAME>Foo</NAME></STMTTRN><STMTTRN><MEMO>x</MEMO></STMTTRN></OFX>`,
+				nil),
+			Entry("when attaching",
+				goofx.OrphanAttach,
+				`<OFX><STMTTRN><NAME>Foo Page 1 of 2</NAME></STMTTRN><STMTTRN><MEMO>x</MEMO></STMTTRN></OFX>`,
+				nil),
+			Entry("when collecting",
+				goofx.OrphanCollect,
+				`<OFX><STMTTRN><NAME>Foo</NAME></STMTTRN><STMTTRN><MEMO>x</MEMO></STMTTRN></OFX>`,
+				[]string{"Page 1 of 2"}),
+		)
+		Context("when there is no previous element to attach to", func() {
+			It("should drop the char data", func() {
+				cleaner := goofx.NewCleaner(goofx.WithOrphanPolicy(goofx.OrphanAttach))
+				err := cleaner.Init([]byte(`<OFX>foo<STMTTRN></STMTTRN></OFX>`))
+				Expect(err).To(BeNil())
+				cleanData, err := cleaner.CleanupXML()
+				Expect(err).To(BeNil())
+				Expect(cleanData.String()).To(Equal(`<OFX><STMTTRN></STMTTRN></OFX>`))
+			})
+		})
+		Context("when the char data trails the document", func() {
+			It("should collect the char data", func() {
+				cleaner := goofx.NewCleaner(goofx.WithOrphanPolicy(goofx.OrphanCollect))
+				err := cleaner.Init([]byte(`<OFX></OFX>Tom &amp; Jerry`))
+				Expect(err).To(BeNil())
+				_, err = cleaner.CleanupXML()
+				Expect(err).To(BeNil())
+				Expect(cleaner.(goofx.FragmentCollector).Fragments()).To(Equal([]string{"Tom & Jerry"}))
+			})
+		})
+	})
+	Describe("Diagnostics()", func() {
+		Context("when tags are not spelled canonically", func() {
+			It("should record each distinct original spelling", func() {
+				cleaner := goofx.NewCleaner()
+				err := cleaner.Init([]byte(`<ofx><stmttrn><name>a</stmttrn><stmttrn><name>b</stmttrn></ofx>`))
+				Expect(err).To(BeNil())
+				_, err = cleaner.CleanupXML()
+				Expect(err).To(BeNil())
+				diagnostics := cleaner.(goofx.Diagnoser).Diagnostics()
+				Expect(diagnostics).To(HaveLen(3))
+				Expect(diagnostics[1]).To(Equal(goofx.Diagnostic{
+					Kind:     goofx.DiagnosticRenamedTag,
+					Tag:      "STMTTRN",
+					Original: "stmttrn",
+					Message:  "tag stmttrn normalized to STMTTRN",
+				}))
+			})
+		})
+	})
+	Describe("Diagnostics()", func() {
+		Context("when element tags are mismatched", func() {
+			It("should record the element the data was assigned to", func() {
+				cleaner := goofx.NewCleaner()
+				err := cleaner.Init([]byte(`<OFX><MEMO>x</NAME></OFX>`))
+				Expect(err).To(BeNil())
+				_, err = cleaner.CleanupXML()
+				Expect(err).To(BeNil())
+				Expect(cleaner.(goofx.Diagnoser).Diagnostics()).To(Equal([]goofx.Diagnostic{{
+					Kind:     goofx.DiagnosticMismatchedClose,
+					Tag:      "MEMO",
+					Original: "NAME",
+					Message:  "charData(x) opened by <MEMO> and closed by </NAME>, closed as </MEMO>",
+				}}))
+			})
+			It("should record the misspelled start tag", func() {
+				cleaner := goofx.NewCleaner()
+				err := cleaner.Init([]byte(`<OFX><STMTTRN><NAEM>Foo</NAME></STMTTRN></OFX>`))
+				Expect(err).To(BeNil())
+				_, err = cleaner.CleanupXML()
+				Expect(err).To(BeNil())
+				Expect(cleaner.(goofx.Diagnoser).Diagnostics()).To(Equal([]goofx.Diagnostic{{
+					Kind:     goofx.DiagnosticMismatchedClose,
+					Tag:      "NAME",
+					Original: "NAEM",
+					Message:  "charData(Foo) opened by <NAEM> and closed by </NAME>, closed as </NAME>",
+				}}))
+			})
+		})
+	})
+	Describe("CleanupXMLContext()", func() {
+		Context("when the context is cancelled", func() {
+			It("should return the context error", func() {
+				data := "<OFX>" + strings.Repeat("<STMTTRN><FITID>1</STMTTRN>", 100) + "</OFX>"
+				cleaner := goofx.NewCleaner().(goofx.ContextCleaner)
+				err := cleaner.Init([]byte(data))
+				Expect(err).To(BeNil())
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				cleanData, err := cleaner.CleanupXMLContext(ctx)
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(cleanData).To(BeNil())
+			})
+		})
+		Context("when the context is not cancelled", func() {
+			It("should parse to clean XML", func() {
+				cleaner := goofx.NewCleaner().(goofx.ContextCleaner)
+				err := cleaner.Init([]byte(`<OFX><STMTTRN><FITID>1</STMTTRN></OFX>`))
+				Expect(err).To(BeNil())
+				cleanData, err := cleaner.CleanupXMLContext(context.Background())
+				Expect(err).To(BeNil())
+				Expect(cleanData.String()).To(Equal(`<OFX><STMTTRN><FITID>1</FITID></STMTTRN></OFX>`))
+			})
+		})
 	})
 })
@@ -0,0 +1,55 @@
+package goofx
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+)
+
+// ctxCheckInterval is the number of tokens processed between cancellation checks.
+const ctxCheckInterval = 256
+
+// contextReader is an io.Reader that stops reading once its context is done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// Read reads from the underlying reader unless the context is done.
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// contextTokenReader is an xml.TokenReader that periodically checks its context
+// for cancellation while reading tokens from the underlying decoder.
+type contextTokenReader struct {
+	ctx    context.Context
+	reader xml.TokenReader
+	count  int
+}
+
+// Token returns the next token from the underlying reader unless the context is done.
+func (r *contextTokenReader) Token() (xml.Token, error) {
+	r.count++
+	if r.count%ctxCheckInterval == 0 {
+		if err := r.ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return r.reader.Token()
+}
+
+// newTokenReader returns an xml.TokenReader for the given data that normalizes tag names,
+// enforces the given limits and returns ctx.Err() once the context is done.
+func newTokenReader(ctx context.Context, data []byte, limits Limits, d *diagnostics) xml.TokenReader {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charsetReader
+	var reader xml.TokenReader = decoder
+	reader = &normalizeTokenReader{reader: reader, diagnostics: d}
+	reader = &limitTokenReader{reader: reader, limits: limits}
+	return &contextTokenReader{ctx: ctx, reader: reader}
+}
@@ -0,0 +1,58 @@
+package goofx
+
+import "github.com/rockstardevs/decimal"
+
+// Currency is a CURRENCY or ORIGCURRENCY aggregate, giving the currency of the amounts of a
+// transaction and the rate converting them to the statement currency (CURDEF).
+type Currency struct {
+	Rate   decimal.Decimal `xml:"CURRATE"`
+	Symbol string          `xml:"CURSYM"`
+	Extra  Extra           `xml:",any"`
+}
+
+// StatementAmount returns the amount of this transaction in the statement currency. Amounts
+// with a CURRENCY are in that currency and converted using its rate, others are returned as is.
+func (t *Transaction) StatementAmount() decimal.Decimal {
+	return statementAmount(t.Amount, t.Currency)
+}
+
+// OriginalAmount returns the amount of this transaction in the currency it was made in, and
+// the symbol of that currency, empty for the statement currency. Amounts with an ORIGCURRENCY
+// are in the statement currency and converted back using its rate, rounded to
+// decimal.DivisionPrecision places.
+func (t *Transaction) OriginalAmount() (decimal.Decimal, string) {
+	return originalAmount(t.Amount, t.Currency, t.OriginalCurrency)
+}
+
+// StatementMarketValue returns the market value of this position in the statement currency,
+// like Transaction.StatementAmount.
+func (p *InvestmentPosition) StatementMarketValue() decimal.Decimal {
+	return statementAmount(p.MarketValue, p.Currency)
+}
+
+// OriginalMarketValue returns the market value of this position in the currency of the
+// security, and the symbol of that currency, like Transaction.OriginalAmount.
+func (p *InvestmentPosition) OriginalMarketValue() (decimal.Decimal, string) {
+	return originalAmount(p.MarketValue, p.Currency, p.OriginalCurrency)
+}
+
+// statementAmount converts the given amount in the given currency, if any, to the statement
+// currency.
+func statementAmount(amount decimal.Decimal, currency *Currency) decimal.Decimal {
+	if currency == nil || currency.Rate.IsZero() {
+		return amount
+	}
+	return amount.Mul(currency.Rate)
+}
+
+// originalAmount returns the given amount in its original currency along with the symbol of
+// that currency, given its CURRENCY or ORIGCURRENCY.
+func originalAmount(amount decimal.Decimal, currency, original *Currency) (decimal.Decimal, string) {
+	switch {
+	case currency != nil:
+		return amount, currency.Symbol
+	case original != nil && !original.Rate.IsZero():
+		return amount.Div(original.Rate), original.Symbol
+	}
+	return amount, ""
+}
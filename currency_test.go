@@ -0,0 +1,82 @@
+package goofx_test
+
+import (
+	"strings"
+
+	"github.com/rockstardevs/decimal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("NewDocumentFromXML() with foreign currency transactions", func() {
+		It("should parse CURRENCY and ORIGCURRENCY", func() {
+			r := strings.NewReader("<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD<BANKTRANLIST>" +
+				"<STMTTRN><TRNTYPE>DEBIT<TRNAMT>-100.00<FITID>1<CURRENCY><CURRATE>1.1<CURSYM>EUR</CURRENCY></STMTTRN>" +
+				"<STMTTRN><TRNTYPE>DEBIT<TRNAMT>-110.00<FITID>2<ORIGCURRENCY><CURRATE>1.1<CURSYM>EUR</ORIGCURRENCY></STMTTRN>" +
+				"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>")
+			d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			txns := *d.GetTxns()
+			Expect(txns[0].Currency.Symbol).To(Equal("EUR"))
+			Expect(txns[0].Currency.Rate.String()).To(Equal("1.1"))
+			Expect(txns[0].OriginalCurrency).To(BeNil())
+			Expect(txns[1].OriginalCurrency.Symbol).To(Equal("EUR"))
+			Expect(txns[0].StatementAmount().String()).To(Equal("-110"))
+			Expect(txns[1].StatementAmount().String()).To(Equal("-110"))
+		})
+	})
+	Describe("NewDocumentFromXML() with foreign currency positions", func() {
+		It("should parse CURRENCY and ORIGCURRENCY of INVPOS", func() {
+			r := strings.NewReader("OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\n\n<OFX><INVSTMTMSGSRSV1><INVSTMTTRNRS>" +
+				"<INVSTMTRS><DTASOF>20190131<CURDEF>USD<INVPOSLIST>" +
+				"<POSSTOCK><INVPOS><SECID><UNIQUEID>1<UNIQUEIDTYPE>CUSIP</SECID><UNITS>10<MKTVAL>100.00" +
+				"<CURRENCY><CURRATE>1.5<CURSYM>EUR</CURRENCY></INVPOS></POSSTOCK>" +
+				"<POSSTOCK><INVPOS><SECID><UNIQUEID>2<UNIQUEIDTYPE>CUSIP</SECID><UNITS>10<MKTVAL>150.00" +
+				"<ORIGCURRENCY><CURRATE>1.5<CURSYM>EUR</ORIGCURRENCY></INVPOS></POSSTOCK>" +
+				"</INVPOSLIST></INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1></OFX>")
+			d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			positions := d.IRMS[0].TRS.RS.Positions.Positions
+			Expect(positions).To(HaveLen(2))
+			Expect(positions[0].Currency.Symbol).To(Equal("EUR"))
+			Expect(positions[0].Currency.Rate.String()).To(Equal("1.5"))
+			Expect(positions[0].OriginalCurrency).To(BeNil())
+			Expect(positions[1].Currency).To(BeNil())
+			Expect(positions[1].OriginalCurrency.Symbol).To(Equal("EUR"))
+			Expect(positions[0].StatementMarketValue().String()).To(Equal("150"))
+			Expect(positions[1].StatementMarketValue().String()).To(Equal("150"))
+			original, symbol := positions[1].OriginalMarketValue()
+			Expect(original.String()).To(Equal("100"))
+			Expect(symbol).To(Equal("EUR"))
+		})
+	})
+	Describe("Transaction", func() {
+		amount := decimal.RequireFromString("-110.00")
+		rate := decimal.RequireFromString("1.1")
+
+		DescribeTable("StatementAmount()", func(t goofx.Transaction, expected string) {
+			Expect(t.StatementAmount().String()).To(Equal(expected))
+		},
+			Entry("statement currency", goofx.Transaction{Amount: amount}, "-110"),
+			Entry("currency", goofx.Transaction{Amount: amount, Currency: &goofx.Currency{Rate: rate, Symbol: "EUR"}}, "-121"),
+			Entry("currency without rate", goofx.Transaction{Amount: amount, Currency: &goofx.Currency{Symbol: "EUR"}}, "-110"),
+			Entry("original currency", goofx.Transaction{Amount: amount, OriginalCurrency: &goofx.Currency{Rate: rate, Symbol: "EUR"}}, "-110"),
+		)
+		DescribeTable("OriginalAmount()", func(t goofx.Transaction, expected, symbol string) {
+			original, currency := t.OriginalAmount()
+			Expect(original.String()).To(Equal(expected))
+			Expect(currency).To(Equal(symbol))
+		},
+			Entry("statement currency", goofx.Transaction{Amount: amount}, "-110", ""),
+			Entry("currency", goofx.Transaction{Amount: amount, Currency: &goofx.Currency{Rate: rate, Symbol: "EUR"}}, "-110", "EUR"),
+			Entry("original currency", goofx.Transaction{Amount: amount, OriginalCurrency: &goofx.Currency{Rate: rate, Symbol: "EUR"}}, "-100", "EUR"),
+			Entry("original currency with rounding", goofx.Transaction{Amount: decimal.New(-1, 0),
+				OriginalCurrency: &goofx.Currency{Rate: decimal.New(3, 0), Symbol: "GBP"}}, "-0.3333333333333333", "GBP"),
+		)
+	})
+})
@@ -0,0 +1,124 @@
+package goofx
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// DiagnosticKind identifies the kind of repair described by a Diagnostic.
+type DiagnosticKind string
+
+const (
+	// DiagnosticRenamedTag is used for tags whose spelling was normalized, e.g. <ofx:stmttrn>.
+	DiagnosticRenamedTag DiagnosticKind = "renamed-tag"
+	// DiagnosticMismatchedClose is used for element data closed by a different end tag,
+	// e.g. <NAME>Foo</NAME1>.
+	DiagnosticMismatchedClose DiagnosticKind = "mismatched-close"
+	// DiagnosticOrphanData is used for char data that has no enclosing element.
+	DiagnosticOrphanData DiagnosticKind = "orphan-data"
+	// DiagnosticUnsupportedTag is used for tags not defined by the version of the spec the
+	// input claims to follow, e.g. <MFACHALLENGERS> in an OFX 1.0.2 file.
+	DiagnosticUnsupportedTag DiagnosticKind = "unsupported-tag"
+)
+
+// Diagnostic describes a repair made to the input while parsing it.
+type Diagnostic struct {
+	Kind     DiagnosticKind
+	Tag      string // Normalized name of the tag the repair applies to.
+	Original string // Tag as spelled in the input.
+	Message  string
+}
+
+// String returns a human readable description of the diagnostic.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Kind, d.Message)
+}
+
+// Diagnoser is implemented by cleaners that record the repairs made while cleaning.
+type Diagnoser interface {
+	Diagnostics() []Diagnostic
+}
+
+// diagnostics collects Diagnostics, recording each renamed spelling and unsupported tag only once.
+type diagnostics struct {
+	items       []Diagnostic
+	renamed     map[string]struct{}
+	schema      *Schema // Schema tags are checked against, none if nil.
+	unsupported map[string]struct{}
+}
+
+// add records a diagnostic.
+func (d *diagnostics) add(kind DiagnosticKind, tag, original, format string, args ...interface{}) {
+	d.items = append(d.items, Diagnostic{
+		Kind:     kind,
+		Tag:      tag,
+		Original: original,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// normalizeName returns the normalized form of the given tag name, recording a diagnostic
+// the first time each distinct spelling is normalized.
+func (d *diagnostics) normalizeName(name xml.Name) xml.Name {
+	normalized := xml.Name{Local: NormalizeTag(name.Local)}
+	d.checkTag(normalized.Local)
+	if name == normalized {
+		return normalized
+	}
+	original := name.Local
+	if name.Space != "" {
+		original = name.Space + ":" + name.Local
+	}
+	if _, found := d.renamed[original]; !found {
+		if d.renamed == nil {
+			d.renamed = make(map[string]struct{})
+		}
+		d.renamed[original] = struct{}{}
+		d.add(DiagnosticRenamedTag, normalized.Local, original, "tag %s normalized to %s", original, normalized.Local)
+	}
+	return normalized
+}
+
+// normalizeTokenReader is an xml.TokenReader that normalizes the names of start and end
+// elements read from the underlying reader.
+type normalizeTokenReader struct {
+	reader      xml.TokenReader
+	diagnostics *diagnostics
+}
+
+// Token returns the next token from the underlying reader with its name normalized.
+func (r *normalizeTokenReader) Token() (xml.Token, error) {
+	token, err := r.reader.Token()
+	if err != nil {
+		return token, err
+	}
+	switch t := token.(type) {
+	case xml.StartElement:
+		t.Name = r.diagnostics.normalizeName(t.Name)
+		return t, nil
+	case xml.EndElement:
+		t.Name = r.diagnostics.normalizeName(t.Name)
+		return t, nil
+	}
+	return token, nil
+}
+
+// checkTag records a diagnostic the first time a tag is seen that is defined by a version of
+// the spec other than the one of this schema.
+func (d *diagnostics) checkTag(name string) {
+	if d.schema == nil || d.schema.IsKnownTag(name) {
+		return
+	}
+	since, found := Since(name)
+	if !found {
+		return
+	}
+	if _, checked := d.unsupported[name]; checked {
+		return
+	}
+	if d.unsupported == nil {
+		d.unsupported = make(map[string]struct{})
+	}
+	d.unsupported[name] = struct{}{}
+	d.add(DiagnosticUnsupportedTag, name, name, "tag %s is defined since OFX %s, not by version %s", name, since, d.schema.Version)
+}
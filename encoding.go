@@ -0,0 +1,165 @@
+package goofx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// encodingSampleSize is the number of bytes used to detect the encoding of the input.
+	encodingSampleSize = 512
+	// utf16ChunkSize is the number of bytes read from UTF-16 input at a time.
+	utf16ChunkSize = 4096
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// detectEncoding detects the encoding of the input from its first bytes. It returns the length
+// of the byte order mark, if any, and the byte order for UTF-16 input or nil for UTF-8 input.
+//
+// UTF-16 input without a byte order mark is detected by the NUL bytes that pad ASCII chars,
+// as OFX data is mostly ASCII.
+func detectEncoding(head []byte) (int, binary.ByteOrder) {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return len(bomUTF8), nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return len(bomUTF16LE), binary.LittleEndian
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return len(bomUTF16BE), binary.BigEndian
+	}
+	var evenZeros, oddZeros int
+	pairs := len(head) / 2
+	for i := 0; i < pairs*2; i += 2 {
+		if head[i] == 0 {
+			evenZeros++
+		}
+		if head[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	switch {
+	case oddZeros*2 > pairs && evenZeros*8 < oddZeros:
+		return 0, binary.LittleEndian
+	case evenZeros*2 > pairs && oddZeros*8 < evenZeros:
+		return 0, binary.BigEndian
+	}
+	return 0, nil
+}
+
+// utf8Reader is an io.Reader that converts UTF-8 or UTF-16 input to UTF-8, removing any
+// byte order mark.
+type utf8Reader struct {
+	reader   io.Reader
+	detected bool
+	order    binary.ByteOrder // Byte order of UTF-16 input, nil for UTF-8 input.
+	in       []byte           // Input read but not converted yet.
+	out      []byte           // Converted output not returned yet.
+	err      error            // Error returned by the last read from reader.
+}
+
+// newUTF8Reader returns a reader that converts the input read from the given reader to UTF-8.
+func newUTF8Reader(reader io.Reader) io.Reader {
+	return &utf8Reader{reader: reader}
+}
+
+// Read reads converted UTF-8 data into p.
+func (r *utf8Reader) Read(p []byte) (int, error) {
+	if !r.detected {
+		r.detect()
+	}
+	for len(r.out) == 0 {
+		if r.order != nil {
+			r.out = r.decode(r.err != nil)
+		} else {
+			r.out, r.in = r.in, nil
+		}
+		if len(r.out) > 0 {
+			break
+		}
+		if r.err != nil {
+			return 0, r.err
+		}
+		chunk := make([]byte, utf16ChunkSize)
+		n, err := r.reader.Read(chunk)
+		r.in = append(r.in, chunk[:n]...)
+		r.err = err
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// detect reads the first bytes of the input and detects its encoding.
+func (r *utf8Reader) detect() {
+	r.detected = true
+	head := make([]byte, encodingSampleSize)
+	n, err := io.ReadFull(r.reader, head)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	r.err = err
+	bom, order := detectEncoding(head[:n])
+	r.in = head[bom:n]
+	r.order = order
+}
+
+// decode converts the UTF-16 input read so far to UTF-8. Unless final is set, a trailing odd
+// byte or high surrogate is kept till the rest of it is read.
+func (r *utf8Reader) decode(final bool) []byte {
+	n := len(r.in) / 2
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = r.order.Uint16(r.in[2*i:])
+	}
+	if !final && n > 0 && units[n-1] >= 0xD800 && units[n-1] < 0xDC00 {
+		n--
+		units = units[:n]
+	}
+	r.in = r.in[2*n:]
+
+	var result bytes.Buffer
+	result.Grow(len(units))
+	for _, c := range utf16.Decode(units) {
+		result.WriteRune(c)
+	}
+	if final && len(r.in) > 0 {
+		result.WriteRune(utf8.RuneError)
+		r.in = nil
+	}
+	return result.Bytes()
+}
+
+// toUTF8 returns the given UTF-8 or UTF-16 data converted to UTF-8, without any byte order mark.
+func toUTF8(data []byte) []byte {
+	head := data
+	if len(head) > encodingSampleSize {
+		head = head[:encodingSampleSize]
+	}
+	if bom, order := detectEncoding(head); bom == 0 && order == nil {
+		return data
+	}
+	// Reading from a bytes.Reader does not fail.
+	result, _ := ioutil.ReadAll(newUTF8Reader(bytes.NewReader(data)))
+	return result
+}
+
+// charsetReader is used as xml.Decoder.CharsetReader for input already converted to UTF-8,
+// whose XML declaration may still name its original encoding.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.Replace(charset, "-", "", -1)) {
+	case "utf16", "utf16le", "utf16be", "usascii", "ascii":
+		return input, nil
+	}
+	return nil, fmt.Errorf("error - unsupported charset %s", charset)
+}
@@ -0,0 +1,76 @@
+package goofx_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing/iotest"
+	"unicode/utf16"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+// encodeUTF16 returns the given string encoded as UTF-16 with the given byte order.
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) []byte {
+	var result bytes.Buffer
+	if bom {
+		_ = binary.Write(&result, order, uint16(0xFEFF))
+	}
+	_ = binary.Write(&result, order, utf16.Encode([]rune(s)))
+	return result.Bytes()
+}
+
+var _ = Describe("goofx", func() {
+	Describe("NewDocumentFromXML()", func() {
+		sgml := "OFXHEADER:100\nDATA:OFXSGML\n\n<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>Café \U0001F3E6</FI></SONRS></SIGNONMSGSRSV1></OFX>"
+		xml := `<?xml version="1.0" encoding="UTF-16"?><?OFX OFXHEADER="200" VERSION="220"?>` +
+			"<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>Café \U0001F3E6</ORG></FI></SONRS></SIGNONMSGSRSV1></OFX>"
+
+		DescribeTable("should detect the encoding and convert to UTF-8", func(data []byte, path goofx.ParsePath) {
+			d, err := goofx.NewDocumentFromXML(iotest.OneByteReader(bytes.NewReader(data)), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Path).To(Equal(path))
+			Expect(d.Response.Organization).To(Equal("Café \U0001F3E6"))
+		},
+			Entry("UTF-8 with BOM", append([]byte{0xEF, 0xBB, 0xBF}, sgml...), goofx.ParsePathCleaned),
+			Entry("UTF-16LE with BOM", encodeUTF16(sgml, binary.LittleEndian, true), goofx.ParsePathCleaned),
+			Entry("UTF-16BE with BOM", encodeUTF16(sgml, binary.BigEndian, true), goofx.ParsePathCleaned),
+			Entry("UTF-16LE without BOM", encodeUTF16(sgml, binary.LittleEndian, false), goofx.ParsePathCleaned),
+			Entry("UTF-16BE without BOM", encodeUTF16(sgml, binary.BigEndian, false), goofx.ParsePathCleaned),
+			Entry("UTF-16LE XML declaring its encoding", encodeUTF16(xml, binary.LittleEndian, true), goofx.ParsePathDirect),
+		)
+	})
+	Describe("Cleaner", func() {
+		It("should convert UTF-16 input to UTF-8", func() {
+			c := goofx.NewCleaner()
+			Expect(c.Init(encodeUTF16("<OFX><ORG>Café</OFX>", binary.LittleEndian, true))).To(Succeed())
+			cleanXML, err := c.CleanupXML()
+			Expect(err).To(BeNil())
+			Expect(cleanXML.String()).To(Equal("<OFX><ORG>Café</ORG></OFX>"))
+		})
+		It("should limit the size of the raw input", func() {
+			data := encodeUTF16("<OFX><ORG>Bank</OFX>", binary.LittleEndian, true)
+			c := goofx.NewCleaner(goofx.WithLimits(goofx.Limits{MaxBytes: int64(len(data)) - 1}))
+			_, err := goofx.NewDocumentFromXML(bytes.NewReader(data), c)
+			Expect(err).To(MatchError(goofx.ErrInputTooLarge))
+		})
+	})
+	Describe("DocumentScanner", func() {
+		It("should convert UTF-16 input to UTF-8", func() {
+			data := strings.Repeat("<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>Bank</FI></SONRS></SIGNONMSGSRSV1></OFX>", 2)
+			r := bytes.NewReader(encodeUTF16(data, binary.LittleEndian, true))
+			scanner := goofx.NewDocumentScanner(r, func() goofx.Cleaner { return goofx.NewCleaner() })
+			count := 0
+			for scanner.Scan() {
+				Expect(scanner.Document().Response.Organization).To(Equal("Bank"))
+				count++
+			}
+			Expect(scanner.Err()).To(BeNil())
+			Expect(count).To(Equal(2))
+		})
+	})
+})
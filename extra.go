@@ -0,0 +1,43 @@
+package goofx
+
+import "encoding/xml"
+
+// ExtraElement is an element or aggregate that is not mapped to a struct field, e.g. CHECKNUM
+// or an INTU.* extension. Aggregates hold their children in order.
+type ExtraElement struct {
+	XMLName  xml.Name
+	Value    string         `xml:",chardata"`
+	Children []ExtraElement `xml:",any"`
+}
+
+// Name returns the tag name of this element.
+func (e ExtraElement) Name() string {
+	return e.XMLName.Local
+}
+
+// Extra holds the children of an aggregate that are not mapped to a struct field, in the
+// order they appear in the input. Aggregates mapped through path tags, such as STATUS in
+// STATUS>CODE, have no Extra: their unmapped children are dropped and not kept in a round trip.
+type Extra []ExtraElement
+
+// Get returns the value of the first element with the given tag.
+func (e Extra) Get(tag string) (string, bool) {
+	tag = NormalizeTag(tag)
+	for _, element := range e {
+		if element.XMLName.Local == tag {
+			return element.Value, true
+		}
+	}
+	return "", false
+}
+
+// Find returns the first element or aggregate with the given tag.
+func (e Extra) Find(tag string) (*ExtraElement, bool) {
+	tag = NormalizeTag(tag)
+	for i := range e {
+		if e[i].XMLName.Local == tag {
+			return &e[i], true
+		}
+	}
+	return nil, false
+}
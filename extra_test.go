@@ -0,0 +1,58 @@
+package goofx_test
+
+import (
+	"encoding/xml"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("NewDocumentFromXML() with unmapped elements", func() {
+		data := "<OFX><SIGNONMSGSRSV1><SONRS><DTSERVER>20190131</DTSERVER><INTU.USERID>jdoe</INTU.USERID></SONRS></SIGNONMSGSRSV1>" +
+			"<BANKMSGSRSV1><STMTTRNRS><TRNUID>1</TRNUID><STMTRS><CURDEF>USD</CURDEF><MKTGINFO>Save more</MKTGINFO>" +
+			"<BANKTRANLIST><STMTTRN><TRNTYPE>CHECK</TRNTYPE><DTPOSTED>20190119</DTPOSTED><TRNAMT>-20.96</TRNAMT>" +
+			"<FITID>1</FITID><INTU.CAT>Groceries</INTU.CAT><INTU.TAG>x</INTU.TAG><INTU.XFER><INTU.ACCT>123</INTU.ACCT></INTU.XFER>" +
+			"</STMTTRN></BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+		get := func(extra goofx.Extra, tag string) string {
+			value, _ := extra.Get(tag)
+			return value
+		}
+
+		It("should capture them into Extra in order", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(get(d.Response.Extra, "INTU.USERID")).To(Equal("jdoe"))
+			rs := d.BRMS[0].TRS.RS
+			Expect(get(rs.Extra, "mktginfo")).To(Equal("Save more"))
+
+			extra := rs.Transactions[0].Extra
+			Expect(extra).To(HaveLen(3))
+			Expect(extra[0].Name()).To(Equal("INTU.CAT"))
+			Expect(extra[1].Name()).To(Equal("INTU.TAG"))
+			xfer, found := extra.Find("INTU.XFER")
+			Expect(found).To(BeTrue())
+			Expect(xfer.Children).To(HaveLen(1))
+			Expect(xfer.Children[0].Name()).To(Equal("INTU.ACCT"))
+			Expect(xfer.Children[0].Value).To(Equal("123"))
+			_, found = extra.Get("MEMO")
+			Expect(found).To(BeFalse())
+		})
+		It("should not lose them in a marshal round trip", func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			marshalled, err := xml.Marshal(d)
+			Expect(err).To(BeNil())
+			for _, tag := range []string{"<INTU.USERID>jdoe</INTU.USERID>", "<MKTGINFO>Save more</MKTGINFO>",
+				"<INTU.CAT>Groceries</INTU.CAT>", "<INTU.XFER><INTU.ACCT>123</INTU.ACCT></INTU.XFER>"} {
+				Expect(string(marshalled)).To(ContainSubstring(tag))
+			}
+			roundTrip, err := goofx.NewDocumentFromXML(strings.NewReader(string(marshalled)), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(roundTrip.BRMS[0].TRS.RS.Transactions[0].Extra).To(Equal(d.BRMS[0].TRS.RS.Transactions[0].Extra))
+		})
+	})
+})
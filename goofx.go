@@ -2,12 +2,13 @@ package goofx
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"io"
-	"io/ioutil"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rockstardevs/decimal"
@@ -18,7 +19,7 @@ import (
 //revive:disable:exported
 //go:generate mockgen -package=mocks -source=cleaner.go -mock_names Cleaner=MockOFXCleaner -destination=mocks/cleaner.go
 
-var txnPattern = regexp.MustCompile(`<STMTTRN>`)
+var txnPattern = regexp.MustCompile(`(?i)<(?:[\w.-]+:)?STMTTRN>`)
 
 // TransactionType is a transaction type as per the OFX Spec 2.2 Section 11.4.4.3
 // https://www.ofx.net/downloads/OFX%202.2.pdf
@@ -47,78 +48,424 @@ const (
 )
 
 type Transaction struct {
-	ID     string          `xml:"_"`
-	Type   TransactionType `xml:"TRNTYPE"`
-	Posted string          `xml:"DTPOSTED"`
-	Amount decimal.Decimal `xml:"TRNAMT"`
-	FitID  string          `xml:"FITID"`
-	Date   string          `xml:"DTUSER,omitempty"`
-	Name   string          `xml:"NAME,omitempty"`
-	Payee  string          `xml:"PAYEE,omitempty"`
-	Memo   string          `xml:"MEMO,omitempty"`
+	ID                  string             `xml:"_"`
+	Type                TransactionType    `xml:"TRNTYPE"`
+	Posted              string             `xml:"DTPOSTED"`
+	Amount              decimal.Decimal    `xml:"TRNAMT"`
+	FitID               string             `xml:"FITID"`
+	Date                string             `xml:"DTUSER,omitempty"`
+	Available           string             `xml:"DTAVAIL,omitempty"`
+	CorrectFitID        string             `xml:"CORRECTFITID,omitempty"`
+	CorrectAction       string             `xml:"CORRECTACTION,omitempty"`
+	ServerID            string             `xml:"SRVRTID,omitempty"`
+	CheckNumber         string             `xml:"CHECKNUM,omitempty"`
+	ReferenceNumber     string             `xml:"REFNUM,omitempty"`
+	SIC                 string             `xml:"SIC,omitempty"`
+	PayeeID             string             `xml:"PAYEEID,omitempty"`
+	Name                string             `xml:"NAME,omitempty"`
+	ExtendedName        string             `xml:"EXTDNAME,omitempty"`
+	Payee               string             `xml:"PAYEE,omitempty"`
+	PayeeDetails        *Payee             `xml:"-"`
+	BankAccountTo       *BankAccount       `xml:"BANKACCTTO,omitempty"`
+	CreditCardAccountTo *CreditCardAccount `xml:"CCACCTTO,omitempty"`
+	Memo                string             `xml:"MEMO,omitempty"`
+	Currency            *Currency          `xml:"CURRENCY,omitempty"`
+	OriginalCurrency    *Currency          `xml:"ORIGCURRENCY,omitempty"`
+	Inv401kSource       string             `xml:"INV401KSOURCE,omitempty"`
+	Extra               Extra              `xml:",any"`
+}
+
+// Payee is the PAYEE aggregate of a transaction. Its name is also set in Transaction.Payee.
+type Payee struct {
+	Name       string `xml:"NAME"`
+	Address1   string `xml:"ADDR1,omitempty"`
+	Address2   string `xml:"ADDR2,omitempty"`
+	Address3   string `xml:"ADDR3,omitempty"`
+	City       string `xml:"CITY,omitempty"`
+	State      string `xml:"STATE,omitempty"`
+	PostalCode string `xml:"POSTALCODE,omitempty"`
+	Country    string `xml:"COUNTRY,omitempty"`
+	Phone      string `xml:"PHONE,omitempty"`
+	Extra      Extra  `xml:",any"`
+}
+
+type transaction Transaction
+
+// transactionXML is the XML form of a Transaction, with PAYEE as either an element holding
+// the name, e.g. <PAYEE>Name, or an aggregate.
+type transactionXML struct {
+	transaction
+	PayeeXML *payeeXML `xml:"PAYEE,omitempty"`
+}
+
+type payeeXML struct {
+	Payee
+	Text string `xml:",chardata"`
+}
+
+func (v *transactionXML) value() Transaction {
+	t := Transaction(v.transaction)
+	if v.PayeeXML == nil {
+		return t
+	}
+	if text := strings.TrimSpace(v.PayeeXML.Text); text != "" {
+		t.Payee = text
+		return t
+	}
+	t.Payee = v.PayeeXML.Name
+	t.PayeeDetails = &v.PayeeXML.Payee
+	return t
+}
+
+func newTransactionXML(t Transaction) transactionXML {
+	v := transactionXML{transaction: transaction(t)}
+	if t.PayeeDetails != nil {
+		v.PayeeXML = &payeeXML{Payee: *t.PayeeDetails}
+	} else if t.Payee != "" {
+		v.PayeeXML = &payeeXML{Text: t.Payee}
+	}
+	return v
+}
+
+// UnmarshalXML decodes a transaction, setting Payee from either form of PAYEE and
+// PayeeDetails from the aggregate.
+func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v transactionXML
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*t = v.value()
+	return nil
+}
+
+// MarshalXML encodes a transaction, with PayeeDetails as the PAYEE aggregate if set.
+func (t Transaction) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(newTransactionXML(t), start)
+}
+
+// String returns the name of the payee.
+func (p *Payee) String() string {
+	return p.Name
+}
+
+// BankAccount identifies a bank account, e.g. in BANKACCTTO.
+type BankAccount struct {
+	BankID    string `xml:"BANKID"`
+	BranchID  string `xml:"BRANCHID,omitempty"`
+	AccountID string `xml:"ACCTID"`
+	Type      string `xml:"ACCTTYPE"`
+	Key       string `xml:"ACCTKEY,omitempty"`
+	Extra     Extra  `xml:",any"`
+}
+
+// CreditCardAccount identifies a credit card account, e.g. in CCACCTTO.
+type CreditCardAccount struct {
+	AccountID string `xml:"ACCTID"`
+	Key       string `xml:"ACCTKEY,omitempty"`
+	Extra     Extra  `xml:",any"`
 }
 
 type SignOnResponse struct {
-	Code           int    `xml:"STATUS>CODE"`
-	Severity       string `xml:"STATUS>SEVERITY"`
-	Date           string `xml:"DTSERVER"`
-	Language       string `xml:"LANGUAGE"`
-	Organization   string `xml:"FI>ORG"`
-	OrganizationID string `xml:"FI>FID"`
-	IntuitID       string `xml:"INTU.BID,omitempty"`
+	Code           StatusCode `xml:"STATUS>CODE"`
+	Severity       Severity   `xml:"STATUS>SEVERITY"`
+	Message        string     `xml:"STATUS>MESSAGE,omitempty"`
+	Date           string     `xml:"DTSERVER"`
+	Language       string     `xml:"LANGUAGE"`
+	Organization   string     `xml:"FI>ORG"`
+	OrganizationID string     `xml:"FI>FID"`
+	IntuitID       string     `xml:"INTU.BID,omitempty"`
+	Extra          Extra      `xml:",any"`
 }
 
 type StatementTransactionResponseSet struct {
 	ID       string               `xml:"TRNUID"`
-	Code     int                  `xml:"STATUS>CODE"`
-	Severity string               `xml:"STATUS>SEVERITY"`
+	Code     StatusCode           `xml:"STATUS>CODE"`
+	Severity Severity             `xml:"STATUS>SEVERITY"`
+	Message  string               `xml:"STATUS>MESSAGE,omitempty"`
 	RS       StatementResponseSet `xml:"STMTRS"`
+	Extra    Extra                `xml:",any"`
 }
 
 type Balance struct {
 	Amount decimal.Decimal `xml:"BALAMT"`
 	Date   string          `xml:"DTASOF"`
+	Extra  Extra           `xml:",any"`
 }
 
 type StatementResponseSet struct {
 	Currency         string        `xml:"CURDEF"`
 	BankID           string        `xml:"BANKACCTFROM>BANKID"`
+	BranchID         string        `xml:"BANKACCTFROM>BRANCHID,omitempty"`
 	AccountID        string        `xml:"BANKACCTFROM>ACCTID"`
 	AccountType      string        `xml:"BANKACCTFROM>ACCTTYPE"`
+	AccountKey       string        `xml:"BANKACCTFROM>ACCTKEY,omitempty"`
 	StartDate        string        `xml:"BANKTRANLIST>DTSTART"`
 	EndDate          string        `xml:"BANKTRANLIST>DTEND"`
 	Transactions     []Transaction `xml:"BANKTRANLIST>STMTTRN"`
 	LedgerBalance    Balance       `xml:"LEDGERBAL"`
 	AvailableBalance Balance       `xml:"AVAILBAL"`
+	Balances         BalanceList   `xml:"BALLIST>BAL,omitempty"`
+	Extra            Extra         `xml:",any"`
 }
 
 type BankResponseMessageSet struct {
-	TRS StatementTransactionResponseSet `xml:"STMTTRNRS"`
+	TRS   StatementTransactionResponseSet `xml:"STMTTRNRS"`
+	Extra Extra                           `xml:",any"`
+}
+
+type CreditCardStatementResponseSet struct {
+	Currency         string            `xml:"CURDEF"`
+	Account          CreditCardAccount `xml:"CCACCTFROM"`
+	StartDate        string            `xml:"BANKTRANLIST>DTSTART"`
+	EndDate          string            `xml:"BANKTRANLIST>DTEND"`
+	Transactions     []Transaction     `xml:"BANKTRANLIST>STMTTRN"`
+	LedgerBalance    Balance           `xml:"LEDGERBAL"`
+	AvailableBalance Balance           `xml:"AVAILBAL"`
+	Balances         BalanceList       `xml:"BALLIST>BAL,omitempty"`
+	Extra            Extra             `xml:",any"`
+}
+
+type CreditCardTransactionResponseSet struct {
+	ID       string                         `xml:"TRNUID"`
+	Code     StatusCode                     `xml:"STATUS>CODE"`
+	Severity Severity                       `xml:"STATUS>SEVERITY"`
+	Message  string                         `xml:"STATUS>MESSAGE,omitempty"`
+	RS       CreditCardStatementResponseSet `xml:"CCSTMTRS"`
+	Extra    Extra                          `xml:",any"`
+}
+
+type CreditCardResponseMessageSet struct {
+	TRS   CreditCardTransactionResponseSet `xml:"CCSTMTTRNRS"`
+	Extra Extra                            `xml:",any"`
+}
+
+// InvestmentAccount identifies an investment account, e.g. in INVACCTFROM.
+type InvestmentAccount struct {
+	BrokerID  string `xml:"BROKERID"`
+	AccountID string `xml:"ACCTID"`
+	Extra     Extra  `xml:",any"`
+}
+
+// InvestmentBankTransaction is an INVBANKTRAN, a cash transaction of an investment account.
+// Security transactions, e.g. BUYSTOCK, are not mapped and kept in the Extra of the
+// InvestmentTransactionList with their nested aggregates.
+type InvestmentBankTransaction struct {
+	Transaction Transaction `xml:"STMTTRN"`
+	SubAccount  string      `xml:"SUBACCTFUND"`
+	Extra       Extra       `xml:",any"`
+}
+
+type InvestmentTransactionList struct {
+	StartDate    string                      `xml:"DTSTART"`
+	EndDate      string                      `xml:"DTEND"`
+	Transactions []InvestmentBankTransaction `xml:"INVBANKTRAN"`
+	Extra        Extra                       `xml:",any"`
+}
+
+// InvestmentBalance is an INVBAL. Its balances are nil if the file has no such element.
+type InvestmentBalance struct {
+	AvailableCash *decimal.Decimal `xml:"AVAILCASH,omitempty"`
+	Margin        *decimal.Decimal `xml:"MARGINBALANCE,omitempty"`
+	Short         *decimal.Decimal `xml:"SHORTBALANCE,omitempty"`
+	BuyingPower   *decimal.Decimal `xml:"BUYPOWER,omitempty"`
+	Balances      BalanceList      `xml:"BALLIST>BAL,omitempty"`
+	Extra         Extra            `xml:",any"`
+}
+
+// InvestmentPosition is a position of an INVPOSLIST, e.g. a POSSTOCK or POSMF aggregate.
+type InvestmentPosition struct {
+	XMLName          xml.Name        // Tag of the position aggregate e.g. POSSTOCK.
+	SecurityID       string          `xml:"INVPOS>SECID>UNIQUEID"`
+	SecurityIDType   string          `xml:"INVPOS>SECID>UNIQUEIDTYPE"`
+	HeldInAccount    string          `xml:"INVPOS>HELDINACCT"`
+	Type             string          `xml:"INVPOS>POSTYPE"`
+	Units            decimal.Decimal `xml:"INVPOS>UNITS"`
+	UnitPrice        decimal.Decimal `xml:"INVPOS>UNITPRICE"`
+	MarketValue      decimal.Decimal `xml:"INVPOS>MKTVAL"`
+	PriceDate        string          `xml:"INVPOS>DTPRICEASOF"`
+	Memo             string          `xml:"INVPOS>MEMO,omitempty"`
+	Currency         *Currency       `xml:"INVPOS>CURRENCY,omitempty"`
+	OriginalCurrency *Currency       `xml:"INVPOS>ORIGCURRENCY,omitempty"`
+	Extra            Extra           `xml:",any"`
+}
+
+// Kind returns the tag of the position aggregate, e.g. POSSTOCK.
+func (p *InvestmentPosition) Kind() string {
+	return p.XMLName.Local
+}
+
+// InvestmentPositionList is an INVPOSLIST, holding positions of any kind in order.
+type InvestmentPositionList struct {
+	Positions []InvestmentPosition `xml:",any"`
+}
+
+type InvestmentStatementResponseSet struct {
+	Date         string                    `xml:"DTASOF"`
+	Currency     string                    `xml:"CURDEF"`
+	Account      InvestmentAccount         `xml:"INVACCTFROM"`
+	Transactions InvestmentTransactionList `xml:"INVTRANLIST"`
+	Positions    InvestmentPositionList    `xml:"INVPOSLIST"`
+	Balance      InvestmentBalance         `xml:"INVBAL"`
+	Extra        Extra                     `xml:",any"`
+}
+
+type InvestmentTransactionResponseSet struct {
+	ID       string                         `xml:"TRNUID"`
+	Code     StatusCode                     `xml:"STATUS>CODE"`
+	Severity Severity                       `xml:"STATUS>SEVERITY"`
+	Message  string                         `xml:"STATUS>MESSAGE,omitempty"`
+	RS       InvestmentStatementResponseSet `xml:"INVSTMTRS"`
+	Extra    Extra                          `xml:",any"`
+}
+
+type InvestmentResponseMessageSet struct {
+	TRS   InvestmentTransactionResponseSet `xml:"INVSTMTTRNRS"`
+	Extra Extra                            `xml:",any"`
+}
+
+// LoanAccount identifies a loan account, e.g. in LOANACCTFROM.
+type LoanAccount struct {
+	AccountID string `xml:"LOANACCTID"`
+	Type      string `xml:"LOANACCTTYPE"`
+	Extra     Extra  `xml:",any"`
+}
+
+// LoanTransaction is a LOANSTMTTRN, a transaction with the split of its amount between
+// principal and interest.
+type LoanTransaction struct {
+	Transaction
+	Principal decimal.Decimal `xml:"LOANTRNAMT>PRINAMT"`
+	Interest  decimal.Decimal `xml:"LOANTRNAMT>INTAMT"`
 }
 
+type loanTransactionXML struct {
+	transactionXML
+	Principal decimal.Decimal `xml:"LOANTRNAMT>PRINAMT"`
+	Interest  decimal.Decimal `xml:"LOANTRNAMT>INTAMT"`
+}
+
+// UnmarshalXML decodes a loan transaction like Transaction.UnmarshalXML.
+func (t *LoanTransaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v loanTransactionXML
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*t = LoanTransaction{Transaction: v.value(), Principal: v.Principal, Interest: v.Interest}
+	return nil
+}
+
+// MarshalXML encodes a loan transaction like Transaction.MarshalXML.
+func (t LoanTransaction) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(loanTransactionXML{newTransactionXML(t.Transaction), t.Principal, t.Interest}, start)
+}
+
+type LoanStatementResponseSet struct {
+	Currency     string            `xml:"CURDEF"`
+	Account      LoanAccount       `xml:"LOANACCTFROM"`
+	StartDate    string            `xml:"LOANTRANLIST>DTSTART"`
+	EndDate      string            `xml:"LOANTRANLIST>DTEND"`
+	Transactions []LoanTransaction `xml:"LOANTRANLIST>LOANSTMTTRN"`
+	Balances     BalanceList       `xml:"BALLIST>BAL,omitempty"`
+	Extra        Extra             `xml:",any"`
+}
+
+type LoanTransactionResponseSet struct {
+	ID       string                   `xml:"TRNUID"`
+	Code     StatusCode               `xml:"STATUS>CODE"`
+	Severity Severity                 `xml:"STATUS>SEVERITY"`
+	Message  string                   `xml:"STATUS>MESSAGE,omitempty"`
+	RS       LoanStatementResponseSet `xml:"LOANSTMTRS"`
+	Extra    Extra                    `xml:",any"`
+}
+
+type LoanResponseMessageSet struct {
+	TRS   LoanTransactionResponseSet `xml:"LOANSTMTTRNRS"`
+	Extra Extra                      `xml:",any"`
+}
+
+// ParsePath identifies how a Document was decoded from its source.
+type ParsePath string
+
+const (
+	// ParsePathDirect is used for input that was well-formed XML and decoded as is.
+	ParsePathDirect ParsePath = "direct"
+	// ParsePathCleaned is used for input that had to be repaired by the cleaner first.
+	ParsePathCleaned ParsePath = "cleaned"
+)
+
 // Document is a parsed OFX/QFX Statement.
 // This does not implement the complete rfc spec yet.
 type Document struct {
-	XMLName          xml.Name                 `xml:"OFX"`
-	Response         SignOnResponse           `xml:"SIGNONMSGSRSV1>SONRS"`
-	BRMS             []BankResponseMessageSet `xml:"BANKMSGSRSV1"`
+	XMLName          xml.Name                       `xml:"OFX"`
+	Header           Header                         `xml:"-"`
+	Response         SignOnResponse                 `xml:"SIGNONMSGSRSV1>SONRS"`
+	BRMS             []BankResponseMessageSet       `xml:"BANKMSGSRSV1"`
+	CCRMS            []CreditCardResponseMessageSet `xml:"CREDITCARDMSGSRSV1"`
+	IRMS             []InvestmentResponseMessageSet `xml:"INVSTMTMSGSRSV1"`
+	LRMS             []LoanResponseMessageSet       `xml:"LOANMSGSRSV1"`
 	TransactionCount int
+	Path             ParsePath    `xml:"-"`
+	Diagnostics      []Diagnostic `xml:"-"` // Repairs made to the input while parsing it.
+	Fragments        []string     `xml:"-"` // Char data without an enclosing element.
+	Quirks           string       `xml:"-"` // Name of the quirk profile applied, if any.
+	Extra            Extra        `xml:",any"`
 }
 
-// NewDocumentFromXML parses the given file into a Document.
-func NewDocumentFromXML(reader io.Reader, cleaner Cleaner) (*Document, error) {
-	cleanXML, err := cleanData(reader, cleaner)
+// NewDocumentFromXML parses the given file into a Document. UTF-16 input and byte order
+// marks are detected and converted to UTF-8.
+//
+// Input that is already well-formed XML (typically OFX 2.x) is decoded directly, otherwise
+// it is repaired by the given cleaner before decoding. Document.Path reports which was used.
+func NewDocumentFromXML(reader io.Reader, cleaner Cleaner, opts ...ParseOption) (*Document, error) {
+	return NewDocumentFromXMLContext(context.Background(), reader, cleaner, opts...)
+}
+
+// NewDocumentFromXMLContext is like NewDocumentFromXML but stops parsing and returns
+// ctx.Err() once the given context is done.
+func NewDocumentFromXMLContext(ctx context.Context, reader io.Reader, cleaner Cleaner, opts ...ParseOption) (*Document, error) {
+	var limits Limits
+	if l, ok := cleaner.(Limiter); ok {
+		limits = l.Limits()
+	}
+	// Parse raw byte from the source file into data.
+	data, err := readData(&contextReader{ctx: ctx, reader: reader}, limits)
 	if err != nil {
 		return nil, err
 	}
+	return newDocument(ctx, data, cleaner, limits, newParseConfig(opts))
+}
 
-	glog.V(3).Infof("cleanXML: %s", cleanXML.String())
-	document := &Document{}
-	if err = xml.Unmarshal(cleanXML.Bytes(), document); err != nil {
+// newDocument parses the given data of a single OFX file into a Document.
+func newDocument(ctx context.Context, data []byte, cleaner Cleaner, limits Limits, config *parseConfig) (*Document, error) {
+	header := ParseHeader(data)
+	data, cleaner, profile, err := prepareData(data, cleaner, config)
+	if err != nil {
 		return nil, err
 	}
 
-	matches := txnPattern.FindAllIndex(cleanXML.Bytes(), -1)
+	var document *Document
+	data, report, err := decodeData(ctx, data, header, cleaner, limits, func(reader xml.TokenReader, path ParsePath) error {
+		document = &Document{Path: path}
+		if f, ok := cleaner.(FragmentCollector); ok && path == ParsePathCleaned {
+			document.Fragments = f.Fragments()
+		}
+		return xml.NewTokenDecoder(reader).Decode(document)
+	})
+	if err != nil {
+		return nil, err
+	}
+	document.Header = header
+	document.Diagnostics = report.items
+	if profile != nil {
+		document.Quirks = profile.Name
+		for _, normalize := range profile.Normalizers {
+			if err = normalize(document); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	matches := txnPattern.FindAllIndex(data, -1)
 	if matches != nil {
 		document.TransactionCount = len(matches)
 	}
@@ -126,22 +473,75 @@ func NewDocumentFromXML(reader io.Reader, cleaner Cleaner) (*Document, error) {
 	return document, nil
 }
 
-func cleanData(reader io.Reader, cleaner Cleaner) (*bytes.Buffer, error) {
-	var (
-		data []byte // Buffer to parse raw bytes from the input file.
-		err  error
-	)
-	// Parse raw byte from the source file into data.
-	if data, err = ioutil.ReadAll(reader); err != nil {
-		return nil, err
+// prepareData applies the configured preprocessors and the quirk profile of the institution
+// that produced the given data, if any. It returns the preprocessed data, the cleaner to use,
+// a copy of the given one with the cleaner options of the profile, and the profile.
+func prepareData(data []byte, cleaner Cleaner, config *parseConfig) ([]byte, Cleaner, *QuirkProfile, error) {
+	preprocessors := config.preprocessors
+	var profile *QuirkProfile
+	if config.quirks != nil {
+		if p, found := config.quirks.lookupData(data); found {
+			glog.V(2).Infof("applying quirk profile %s", p.Name)
+			profile = p
+			preprocessors = append(append([]Preprocessor{}, preprocessors...), p.Preprocessors...)
+			if c, ok := cleaner.(Configurable); ok && len(p.CleanerOptions) > 0 {
+				cleaner = c.WithOptions(p.CleanerOptions...)
+			}
+		}
+	}
+	data, err := preprocess(data, preprocessors, config.disabled)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	return data, cleaner, profile, nil
+}
 
-	data = preprocessOFXData(data)
-	err = cleaner.Init(data)
+// decodeData calls decode with a token reader for the given data if it is well-formed XML
+// without char data outside of elements, otherwise with one for the data cleaned by the given
+// cleaner. It returns the data decoded and the repairs made to it.
+func decodeData(ctx context.Context, data []byte, header Header, cleaner Cleaner, limits Limits,
+	decode func(xml.TokenReader, ParsePath) error) ([]byte, *diagnostics, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	report := &diagnostics{schema: SchemaForVersion(header.Version)}
+	err := decode(&orphanTokenReader{reader: newTokenReader(ctx, data, limits, report)}, ParsePathDirect)
+	if err == nil {
+		return data, report, nil
+	}
+	if ctx.Err() != nil || isLimitError(err) {
+		return nil, nil, err
+	}
+	glog.V(2).Infof("direct decoding failed, falling back to cleaner: %s", err)
+
+	cleanXML, err := cleanData(ctx, data, cleaner)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	glog.V(3).Infof("cleanXML: %s", cleanXML.String())
+	data = cleanXML.Bytes()
 
+	report = &diagnostics{}
+	if d, ok := cleaner.(Diagnoser); ok {
+		report.items = d.Diagnostics()
+	}
+	if err := decode(newTokenReader(ctx, data, Limits{}, report), ParsePathCleaned); err != nil {
+		return nil, nil, err
+	}
+	return data, report, nil
+}
+
+// cleanData runs the given cleaner on the given data.
+func cleanData(ctx context.Context, data []byte, cleaner Cleaner) (*bytes.Buffer, error) {
+	if err := cleaner.Init(data); err != nil {
+		return nil, err
+	}
+	if c, ok := cleaner.(ContextCleaner); ok {
+		return c.CleanupXMLContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return cleaner.CleanupXML()
 }
 
@@ -2,6 +2,8 @@ package goofx_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/xml"
 	"errors"
 	"strings"
 	"time"
@@ -78,6 +80,25 @@ var _ = Describe("goofx", func() {
 				Expect(d).To(BeNil())
 			})
 		})
+		Context("when given a file larger than the size limit", func() {
+			It("should return an error", func() {
+				r := strings.NewReader("<OFX><STMTTRN><FITID>1</STMTTRN></OFX>")
+				c := goofx.NewCleaner(goofx.WithLimits(goofx.Limits{MaxBytes: 16}))
+				d, err := goofx.NewDocumentFromXML(r, c)
+				Expect(err).To(MatchError(goofx.ErrInputTooLarge))
+				Expect(d).To(BeNil())
+			})
+		})
+		Context("when given a file at the size limit grown by preprocessing", func() {
+			It("should parse it", func() {
+				data := "<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD</CURDEF>\n<BANKID>1<ACCTID>2" +
+					"</BANKACCTFROM></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+				c := goofx.NewCleaner(goofx.WithLimits(goofx.Limits{MaxBytes: int64(len(data))}))
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(data), c)
+				Expect(err).To(BeNil())
+				Expect(d.BRMS[0].TRS.RS.AccountID).To(Equal("2"))
+			})
+		})
 		Context("when given invalid XML", func() {
 			It("should return an error", func() {
 				r := strings.NewReader("")
@@ -109,6 +130,130 @@ var _ = Describe("goofx", func() {
 				Expect(err).To(BeNil())
 				Expect(d).NotTo(BeNil())
 			})
+			It("should decode well-formed XML directly", func() {
+				r := strings.NewReader(`<?xml version="1.0"?><?OFX OFXHEADER="200" VERSION="220"?>
+					<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+						<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><TRNAMT>-1.00</TRNAMT><NAME>A &amp; B  Co</NAME></STMTTRN>
+					</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`)
+				d, err := goofx.NewDocumentFromXML(r, &FakeCleaner{err: errors.New("cleaner should not be used")})
+				Expect(err).To(BeNil())
+				Expect(d.Path).To(Equal(goofx.ParsePathDirect))
+				Expect(d.TransactionCount).To(Equal(1))
+				Expect(*d.GetTxns()).To(HaveLen(1))
+				Expect((*d.GetTxns())[0].Name).To(Equal("A & B  Co"))
+			})
+			It("should fall back to the cleaner for malformed XML", func() {
+				r := strings.NewReader("<OFX><STMTTRN><FITID>1</STMTTRN></OFX>")
+				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(d.Path).To(Equal(goofx.ParsePathCleaned))
+			})
+			It("should resolve entities in otherwise well-formed XML", func() {
+				r := strings.NewReader("<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>" +
+					"<STMTTRN><NAME>Caf&eacute; & Bar</NAME></STMTTRN>" +
+					"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>")
+				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(d.Path).To(Equal(goofx.ParsePathCleaned))
+				Expect((*d.GetTxns())[0].Name).To(Equal("Caf\u00e9 & Bar"))
+			})
+			It("should normalize tag names in well-formed XML", func() {
+				r := strings.NewReader(`<ofx:OFX xmlns:ofx="http://ofx.net/types/2003/04"><ofx:bankmsgsrsv1><stmttrnrs><stmtrs><banktranlist>
+						<stmttrn><trntype>DEBIT</trntype><trnamt>-1.00</trnamt></stmttrn>
+					</banktranlist></stmtrs></stmttrnrs></ofx:bankmsgsrsv1></ofx:OFX>`)
+				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(d.Path).To(Equal(goofx.ParsePathDirect))
+				Expect(d.TransactionCount).To(Equal(1))
+				Expect(*d.GetTxns()).To(HaveLen(1))
+				Expect((*d.GetTxns())[0].Type).To(Equal(goofx.DEBIT))
+				Expect(d.Diagnostics).NotTo(BeEmpty())
+				Expect(d.Diagnostics[0].Tag).To(Equal("OFX"))
+			})
+			It("should enforce limits on well-formed XML", func() {
+				r := strings.NewReader("<OFX><STMTTRN></STMTTRN><STMTTRN></STMTTRN></OFX>")
+				c := goofx.NewCleaner(goofx.WithLimits(goofx.Limits{MaxTransactions: 1}))
+				d, err := goofx.NewDocumentFromXML(r, c)
+				Expect(err).To(MatchError(goofx.ErrTooManyTransactions))
+				Expect(d).To(BeNil())
+			})
+			It("should collect char data without an enclosing element", func() {
+				r := strings.NewReader("<OFX><BANKMSGSRSV1>Page 2</OFX>")
+				c := goofx.NewCleaner(goofx.WithOrphanPolicy(goofx.OrphanCollect))
+				d, err := goofx.NewDocumentFromXML(r, c)
+				Expect(err).To(BeNil())
+				Expect(d.Fragments).To(Equal([]string{"Page 2"}))
+				Expect(d.Diagnostics).To(HaveLen(1))
+				Expect(d.Diagnostics[0].Kind).To(Equal(goofx.DiagnosticOrphanData))
+			})
+			DescribeTable("char data without an enclosing element in well-formed XML",
+				func(data string, policy goofx.OrphanPolicy, fragments []string) {
+					d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner(goofx.WithOrphanPolicy(policy)))
+					if fragments == nil {
+						Expect(err).NotTo(BeNil())
+						return
+					}
+					Expect(err).To(BeNil())
+					Expect(d.Path).To(Equal(goofx.ParsePathCleaned))
+					Expect(d.Fragments).To(ConsistOf(fragments))
+					Expect(d.Response.Code).To(Equal(goofx.StatusSuccess))
+				},
+				Entry("after a child fails by default",
+					"<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE></STATUS></SONRS>footer</SIGNONMSGSRSV1></OFX>",
+					goofx.OrphanError, nil),
+				Entry("before a child fails by default",
+					"<OFX><SIGNONMSGSRSV1>header<SONRS><STATUS><CODE>0</CODE></STATUS></SONRS></SIGNONMSGSRSV1></OFX>",
+					goofx.OrphanError, nil),
+				Entry("is collected",
+					"<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE></STATUS></SONRS>footer</SIGNONMSGSRSV1></OFX>",
+					goofx.OrphanCollect, []string{"footer"}),
+				Entry("is dropped",
+					"<OFX><SIGNONMSGSRSV1>header<SONRS><STATUS><CODE>0</CODE></STATUS></SONRS></SIGNONMSGSRSV1></OFX>",
+					goofx.OrphanDrop, []string{}),
+			)
+			It("should map the extended transaction fields", func() {
+				r := strings.NewReader("<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>" +
+					"<STMTTRN><TRNTYPE>CHECK<DTPOSTED>20190119<DTAVAIL>20190120<TRNAMT>-20.96<FITID>2" +
+					"<CORRECTFITID>1<CORRECTACTION>REPLACE<SRVRTID>S1<CHECKNUM>1001<REFNUM>R1<SIC>5411<PAYEEID>P1" +
+					"<EXTDNAME>Extended<PAYEE><NAME>Shop<ADDR1>1 Main St<CITY>Springfield<STATE>IL<POSTALCODE>62701" +
+					"<PHONE>555-0100</PAYEE><MEMO>Memo<INV401KSOURCE>PRETAX</STMTTRN>" +
+					"<STMTTRN><TRNTYPE>XFER<TRNAMT>-5<FITID>3<PAYEE>Name only" +
+					"<BANKACCTTO><BANKID>1<ACCTID>2<ACCTTYPE>SAVINGS</BANKACCTTO></STMTTRN>" +
+					"<STMTTRN><TRNTYPE>PAYMENT<TRNAMT>-6<FITID>4<CCACCTTO><ACCTID>4111</CCACCTTO></STMTTRN>" +
+					"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>")
+				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				txns := *d.GetTxns()
+				Expect(txns).To(HaveLen(3))
+				Expect(txns[0]).To(Equal(goofx.Transaction{
+					Type: goofx.CHECK, Posted: "20190119", Available: "20190120", Amount: decimal.New(-2096, -2),
+					FitID: "2", CorrectFitID: "1", CorrectAction: "REPLACE", ServerID: "S1", CheckNumber: "1001",
+					ReferenceNumber: "R1", SIC: "5411", PayeeID: "P1", ExtendedName: "Extended",
+					Payee: "Shop", PayeeDetails: &goofx.Payee{Name: "Shop", Address1: "1 Main St", City: "Springfield",
+						State: "IL", PostalCode: "62701", Phone: "555-0100"},
+					Memo: "Memo", Inv401kSource: "PRETAX",
+				}))
+				Expect(txns[1].Payee).To(Equal("Name only"))
+				Expect(txns[1].PayeeDetails).To(BeNil())
+				Expect(txns[1].BankAccountTo).To(Equal(&goofx.BankAccount{BankID: "1", AccountID: "2", Type: "SAVINGS"}))
+				Expect(txns[2].Payee).To(BeEmpty())
+				Expect(txns[2].CreditCardAccountTo).To(Equal(&goofx.CreditCardAccount{AccountID: "4111"}))
+				Expect(d.Diagnostics).To(BeEmpty())
+			})
+			It("should keep the form of PAYEE in a marshal round trip", func() {
+				txns := []goofx.Transaction{
+					{Payee: "Shop", PayeeDetails: &goofx.Payee{Name: "Shop", City: "Springfield"}},
+					{Payee: "Name only"},
+				}
+				for _, txn := range txns {
+					marshalled, err := xml.Marshal(txn)
+					Expect(err).To(BeNil())
+					var roundTrip goofx.Transaction
+					Expect(xml.Unmarshal(marshalled, &roundTrip)).To(Succeed())
+					Expect(roundTrip.Payee).To(Equal(txn.Payee))
+					Expect(roundTrip.PayeeDetails).To(Equal(txn.PayeeDetails))
+				}
+			})
 			It("should set txn count", func() {
 				r := strings.NewReader("<OFX><STMTTRN><FITID>1</STMTTRN><STMTTRN>2</FITID></STMTTRN></OFX>")
 				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
@@ -118,6 +263,36 @@ var _ = Describe("goofx", func() {
 			})
 		})
 	})
+	Describe("NewDocumentFromXMLContext()", func() {
+		Context("when the context is cancelled", func() {
+			It("should return the context error", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				r := strings.NewReader("<OFX></OFX>")
+				d, err := goofx.NewDocumentFromXMLContext(ctx, r, goofx.NewCleaner())
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(d).To(BeNil())
+			})
+		})
+		Context("when the context times out", func() {
+			It("should return the context error", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+				defer cancel()
+				r := strings.NewReader("<OFX></OFX>")
+				d, err := goofx.NewDocumentFromXMLContext(ctx, r, &FakeCleaner{data: "<OFX></OFX>"})
+				Expect(err).To(MatchError(context.DeadlineExceeded))
+				Expect(d).To(BeNil())
+			})
+		})
+		Context("when the context is not done", func() {
+			It("should return an initialized document", func() {
+				r := strings.NewReader("<OFX><STMTTRN><FITID>1</STMTTRN></OFX>")
+				d, err := goofx.NewDocumentFromXMLContext(context.Background(), r, goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(d.TransactionCount).To(Equal(1))
+			})
+		})
+	})
 	Describe("Document", func() {
 		Describe("GetTxns()", func() {
 			Context("when document has no txns", func() {
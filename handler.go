@@ -0,0 +1,92 @@
+package goofx
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// Handler receives the events of a file as it is cleaned, in document order. Names are
+// normalized and element values unescaped. Returning an error stops parsing.
+type Handler interface {
+	// StartAggregate is called when an aggregate opens.
+	StartAggregate(name string) error
+	// EndAggregate is called when an aggregate closes, including closing tags the cleaner inferred.
+	EndAggregate(name string) error
+	// Element is called when an element and its value are complete.
+	Element(name, value string) error
+}
+
+// HandlerFuncs is a Handler calling the set functions, ignoring events without one.
+type HandlerFuncs struct {
+	OnStartAggregate func(name string) error
+	OnEndAggregate   func(name string) error
+	OnElement        func(name, value string) error
+}
+
+// StartAggregate calls OnStartAggregate if set.
+func (h HandlerFuncs) StartAggregate(name string) error {
+	if h.OnStartAggregate == nil {
+		return nil
+	}
+	return h.OnStartAggregate(name)
+}
+
+// EndAggregate calls OnEndAggregate if set.
+func (h HandlerFuncs) EndAggregate(name string) error {
+	if h.OnEndAggregate == nil {
+		return nil
+	}
+	return h.OnEndAggregate(name)
+}
+
+// Element calls OnElement if set.
+func (h HandlerFuncs) Element(name, value string) error {
+	if h.OnElement == nil {
+		return nil
+	}
+	return h.OnElement(name, value)
+}
+
+// WithHandler sets the handler receiving the events of the data as it is cleaned. The cleaned
+// XML is then not kept, CleanupXML returns an empty buffer. With OrphanAttach, the event of an
+// element is sent with the next event, so data attached to it is part of its value. Data
+// after an aggregate event is dropped instead, as the element was already sent.
+func WithHandler(handler Handler) CleanerOption {
+	return func(c *cleaner) {
+		c.handler = handler
+	}
+}
+
+// ParseWithHandler cleans the given file, calling handler for each aggregate and element
+// instead of building a Document. The cleaner must accept a handler, as those returned by
+// NewCleaner do.
+//
+// The whole file is read in memory, since preprocessors and quirk profiles work on all of it,
+// but neither the cleaned XML nor a Document is built from it.
+func ParseWithHandler(reader io.Reader, cleaner Cleaner, handler Handler, opts ...ParseOption) error {
+	return ParseWithHandlerContext(context.Background(), reader, cleaner, handler, opts...)
+}
+
+// ParseWithHandlerContext is like ParseWithHandler but stops parsing and returns ctx.Err()
+// once the given context is done.
+func ParseWithHandlerContext(ctx context.Context, reader io.Reader, cleaner Cleaner, handler Handler, opts ...ParseOption) error {
+	if _, ok := cleaner.(Configurable); !ok {
+		return errors.New("error - cleaner does not accept a handler")
+	}
+	var limits Limits
+	if l, ok := cleaner.(Limiter); ok {
+		limits = l.Limits()
+	}
+	data, err := readData(&contextReader{ctx: ctx, reader: reader}, limits)
+	if err != nil {
+		return err
+	}
+	data, cleaner, _, err = prepareData(data, cleaner, newParseConfig(opts))
+	if err != nil {
+		return err
+	}
+	// The cleaner may be a copy with the options of a quirk profile, which is Configurable too.
+	_, err = cleanData(ctx, data, cleaner.(Configurable).WithOptions(WithHandler(handler)))
+	return err
+}
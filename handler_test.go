@@ -0,0 +1,89 @@
+package goofx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+	"github.com/rockstardevs/goofx/mocks"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("ParseWithHandler()", func() {
+		data := "<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS>" +
+			"<FI><ORG>Test &amp; Bank</FI></SIGNONMSGSRSV1><bankmsgsrsv1><STMTTRN><TRNAMT>-1.00</STMTTRN></OFX>"
+		var (
+			events  []string
+			handler goofx.Handler
+		)
+		BeforeEach(func() {
+			events = nil
+			handler = goofx.HandlerFuncs{
+				OnStartAggregate: func(name string) error {
+					events = append(events, "<"+name+">")
+					return nil
+				},
+				OnEndAggregate: func(name string) error {
+					events = append(events, "</"+name+">")
+					return nil
+				},
+				OnElement: func(name, value string) error {
+					events = append(events, name+"="+value)
+					return nil
+				},
+			}
+		})
+
+		It("should call the handler for each repaired event", func() {
+			Expect(goofx.ParseWithHandler(strings.NewReader(data), goofx.NewCleaner(), handler)).To(Succeed())
+			Expect(events).To(Equal([]string{
+				"<OFX>", "<SIGNONMSGSRSV1>", "<SONRS>", "<STATUS>", "CODE=0", "SEVERITY=INFO", "</STATUS>",
+				"<FI>", "ORG=Test & Bank", "</FI>", "</SONRS>", "</SIGNONMSGSRSV1>",
+				"<BANKMSGSRSV1>", "<STMTTRN>", "TRNAMT=-1.00", "</STMTTRN>", "</BANKMSGSRSV1>", "</OFX>",
+			}))
+		})
+		It("should not keep the cleaned XML", func() {
+			cleaner := goofx.NewCleaner(goofx.WithHandler(handler))
+			Expect(cleaner.Init([]byte(data))).To(Succeed())
+			cleaned, err := cleaner.CleanupXML()
+			Expect(err).To(BeNil())
+			Expect(cleaned.Len()).To(BeZero())
+			Expect(events).To(HaveLen(18))
+		})
+		It("should send elements with data attached by OrphanAttach", func() {
+			data := "<OFX><STMTTRN><NAME>Foo</NAME>Page 2<MEMO>x</STMTTRN>Footer</OFX>"
+			cleaner := goofx.NewCleaner(goofx.WithOrphanPolicy(goofx.OrphanAttach))
+			Expect(goofx.ParseWithHandler(strings.NewReader(data), cleaner, handler)).To(Succeed())
+			Expect(events).To(Equal([]string{"<OFX>", "<STMTTRN>", "NAME=Foo Page 2", "MEMO=x", "</STMTTRN>", "</OFX>"}))
+		})
+		It("should stop at the first handler error", func() {
+			stop := errors.New("stop")
+			err := goofx.ParseWithHandler(strings.NewReader(data), goofx.NewCleaner(), goofx.HandlerFuncs{
+				OnElement: func(name, value string) error {
+					events = append(events, name)
+					return stop
+				},
+			})
+			Expect(err).To(Equal(stop))
+			Expect(events).To(Equal([]string{"CODE"}))
+		})
+		It("should ignore events without a function", func() {
+			Expect(goofx.ParseWithHandler(strings.NewReader(data), goofx.NewCleaner(), goofx.HandlerFuncs{})).To(Succeed())
+		})
+		It("should stop once the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := goofx.ParseWithHandlerContext(ctx, strings.NewReader(data), goofx.NewCleaner(), handler)
+			Expect(err).To(Equal(context.Canceled))
+			Expect(events).To(BeEmpty())
+		})
+		It("should fail for cleaners not accepting a handler", func() {
+			err := goofx.ParseWithHandler(strings.NewReader(data), &mocks.MockOFXCleaner{}, handler)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
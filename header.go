@@ -0,0 +1,87 @@
+package goofx
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// sgmlHeaderPattern matches the KEY:VALUE lines of an OFX 1.x header.
+	sgmlHeaderPattern = regexp.MustCompile(`(?m)^\s*([A-Za-z]+)\s*:[ \t]*(\S*)[ \t]*\r?$`)
+	// xmlHeaderPattern matches the OFX processing instruction of an OFX 2.x header.
+	xmlHeaderPattern = regexp.MustCompile(`(?i)<\?OFX\s([^>]*)\?>`)
+	// xmlDeclPattern matches the XML declaration preceding an OFX 2.x header.
+	xmlDeclPattern = regexp.MustCompile(`(?i)<\?xml\s([^>]*)\?>`)
+	// attrPattern matches the pseudo-attributes of a processing instruction.
+	attrPattern = regexp.MustCompile(`([A-Za-z]+)\s*=\s*["']([^"']*)["']`)
+	// headerStartPattern matches the start of an OFX 1.x or 2.x header.
+	headerStartPattern = regexp.MustCompile(`(?i)(?:<\?xml[^>]*>\s*)?(?:<\?OFX[\s?]|OFXHEADER\s*:)`)
+)
+
+// Header is the header preceding the OFX data, either the KEY:VALUE lines used by
+// OFX 1.x or the OFX processing instruction used by OFX 2.x.
+type Header struct {
+	OFXHeader   string // OFXHEADER, 100 for OFX 1.x and 200 for OFX 2.x.
+	Data        string // DATA, OFXSGML for OFX 1.x.
+	Version     string // VERSION e.g. 102 or 220.
+	Security    string // SECURITY e.g. NONE or TYPE1.
+	Encoding    string // ENCODING e.g. USASCII, UTF-8.
+	Charset     string // CHARSET e.g. 1252, only used by OFX 1.x.
+	Compression string // COMPRESSION, only used by OFX 1.x.
+	OldFileUID  string // OLDFILEUID.
+	NewFileUID  string // NEWFILEUID.
+}
+
+// ParseHeader parses the header preceding the OFX tag in the given data. Missing or
+// unknown fields are ignored.
+func ParseHeader(data []byte) Header {
+	if i := findOFXStart(data); i != -1 {
+		data = data[:i]
+	}
+	var (
+		header Header
+		fields = map[string]*string{
+			"OFXHEADER":   &header.OFXHeader,
+			"DATA":        &header.Data,
+			"VERSION":     &header.Version,
+			"SECURITY":    &header.Security,
+			"ENCODING":    &header.Encoding,
+			"CHARSET":     &header.Charset,
+			"COMPRESSION": &header.Compression,
+			"OLDFILEUID":  &header.OldFileUID,
+			"NEWFILEUID":  &header.NewFileUID,
+		}
+		set = func(key, value string) {
+			if field, found := fields[strings.ToUpper(key)]; found {
+				*field = value
+			}
+		}
+	)
+	if m := xmlHeaderPattern.FindSubmatch(data); m != nil {
+		if d := xmlDeclPattern.FindSubmatch(data); d != nil {
+			for _, attr := range attrPattern.FindAllSubmatch(d[1], -1) {
+				if strings.EqualFold(string(attr[1]), "encoding") {
+					header.Encoding = string(attr[2])
+				}
+			}
+		}
+		for _, attr := range attrPattern.FindAllSubmatch(m[1], -1) {
+			set(string(attr[1]), string(attr[2]))
+		}
+		return header
+	}
+	for _, m := range sgmlHeaderPattern.FindAllSubmatch(data, -1) {
+		set(string(m[1]), string(m[2]))
+	}
+	return header
+}
+
+// lastHeaderStart returns the index of the start of the last header in the given data,
+// or -1 if not found.
+func lastHeaderStart(data []byte) int {
+	matches := headerStartPattern.FindAllIndex(data, -1)
+	if len(matches) == 0 {
+		return -1
+	}
+	return matches[len(matches)-1][0]
+}
@@ -0,0 +1,34 @@
+package goofx_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("ParseHeader()", func() {
+		DescribeTable("should parse the header preceding the OFX tag", func(data string, expected goofx.Header) {
+			Expect(goofx.ParseHeader([]byte(data))).To(Equal(expected))
+		},
+			Entry("OFX 1.x header",
+				"OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:USASCII\r\n"+
+					"CHARSET:1252\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n<OFX></OFX>",
+				goofx.Header{
+					OFXHeader: "100", Data: "OFXSGML", Version: "102", Security: "NONE", Encoding: "USASCII",
+					Charset: "1252", Compression: "NONE", OldFileUID: "NONE", NewFileUID: "NONE",
+				}),
+			Entry("OFX 2.x header",
+				`<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+
+					`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?><OFX></OFX>`,
+				goofx.Header{
+					OFXHeader: "200", Version: "220", Security: "NONE", Encoding: "UTF-8",
+					OldFileUID: "NONE", NewFileUID: "NONE",
+				}),
+			Entry("header after the OFX tag", "<OFX>VERSION:102</OFX>", goofx.Header{}),
+			Entry("missing header", "<OFX></OFX>", goofx.Header{}),
+		)
+	})
+})
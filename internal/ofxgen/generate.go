@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// builtinTypes maps XSD built-in types to Go types. OFX values such as amounts and dates
+// have their own formats, so they are kept as strings.
+var builtinTypes = map[string]string{
+	"string": "string", "normalizedString": "string", "token": "string", "anyURI": "string",
+	"ID": "string", "IDREF": "string", "date": "string", "dateTime": "string", "time": "string",
+	"decimal": "string", "base64Binary": "string", "hexBinary": "string",
+	"int": "int", "integer": "int", "long": "int64", "short": "int", "positiveInteger": "int",
+	"nonNegativeInteger": "int", "unsignedInt": "int",
+	"boolean": "bool",
+}
+
+// field is a field of a generated struct.
+type field struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// structType is a generated struct.
+type structType struct {
+	Name          string
+	Documentation string
+	Fields        []field
+}
+
+// generator generates Go types from parsed XSD schemas.
+type generator struct {
+	pkg     string
+	version string
+
+	elements     map[string]*xsdElement
+	complexTypes map[string]*xsdComplexType
+	simpleTypes  map[string]*xsdSimpleType
+	complexOrder []*xsdComplexType
+	simpleOrder  []*xsdSimpleType
+	globals      []*xsdElement
+
+	structs     []*structType
+	structNames map[string]bool
+	aggregates  map[string]struct{}
+	tags        map[string]struct{}
+}
+
+// newGenerator returns a generator for types in the given package from the given schemas.
+func newGenerator(pkg, version string, schemas []*xsdSchema) *generator {
+	g := &generator{
+		pkg:          pkg,
+		version:      version,
+		elements:     make(map[string]*xsdElement),
+		complexTypes: make(map[string]*xsdComplexType),
+		simpleTypes:  make(map[string]*xsdSimpleType),
+		structNames:  make(map[string]bool),
+		aggregates:   make(map[string]struct{}),
+		tags:         make(map[string]struct{}),
+	}
+	for _, s := range schemas {
+		for i := range s.Elements {
+			g.elements[s.Elements[i].Name] = &s.Elements[i]
+			g.globals = append(g.globals, &s.Elements[i])
+		}
+		for i := range s.ComplexTypes {
+			g.complexTypes[s.ComplexTypes[i].Name] = &s.ComplexTypes[i]
+			g.complexOrder = append(g.complexOrder, &s.ComplexTypes[i])
+		}
+		for i := range s.SimpleTypes {
+			g.simpleTypes[s.SimpleTypes[i].Name] = &s.SimpleTypes[i]
+			g.simpleOrder = append(g.simpleOrder, &s.SimpleTypes[i])
+		}
+	}
+	return g
+}
+
+// generate returns the formatted Go source for the schemas read from the given sources.
+func (g *generator) generate(sources []string) ([]byte, error) {
+	for _, ct := range g.complexOrder {
+		if _, err := g.addStruct(exportName(ct.Name), ct); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range g.globals {
+		if _, err := g.elementType(e.Name, e); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by ofxgen from %s. DO NOT EDIT.\n\n", strings.Join(sources, ", "))
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg)
+	buf.WriteString("func init() {\n")
+	fmt.Fprintf(&buf, "Version = %q\n", g.version)
+	fmt.Fprintf(&buf, "Aggregates = append(Aggregates, %s)\n", quoteAll(sortedKeys(g.aggregates)))
+	elements := make(map[string]struct{})
+	for tag := range g.tags {
+		if _, found := g.aggregates[tag]; !found {
+			elements[tag] = struct{}{}
+		}
+	}
+	fmt.Fprintf(&buf, "Elements = append(Elements, %s)\n", quoteAll(sortedKeys(elements)))
+	buf.WriteString("}\n")
+
+	for _, st := range g.simpleOrder {
+		if len(st.Restriction.Enumerations) == 0 {
+			continue
+		}
+		name := exportName(st.Name)
+		buf.WriteString("\n")
+		writeDoc(&buf, name, st.Documentation)
+		fmt.Fprintf(&buf, "type %s string\n\nconst (\n", name)
+		for _, e := range st.Restriction.Enumerations {
+			fmt.Fprintf(&buf, "%s%s %s = %q\n", name, goName(e.Value), name, e.Value)
+		}
+		buf.WriteString(")\n")
+	}
+	for _, s := range g.structs {
+		buf.WriteString("\n")
+		writeDoc(&buf, s.Name, s.Documentation)
+		fmt.Fprintf(&buf, "type %s struct {\n", s.Name)
+		for _, f := range s.Fields {
+			// omitempty has no effect on struct fields, optional ones are pointers.
+			if g.structNames[f.Type] {
+				fmt.Fprintf(&buf, "%s %s `xml:\"%s\"`\n", f.Name, f.Type, f.Tag)
+			} else {
+				fmt.Fprintf(&buf, "%s %s `xml:\"%s,omitempty\"`\n", f.Name, f.Type, f.Tag)
+			}
+		}
+		buf.WriteString("}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// addStruct adds a struct with the given name for the given complex type.
+func (g *generator) addStruct(name string, ct *xsdComplexType) (string, error) {
+	if g.structNames[name] {
+		return name, nil
+	}
+	g.structNames[name] = true
+	s := &structType{Name: name, Documentation: ct.Documentation}
+	g.structs = append(g.structs, s)
+	fields, err := g.fields(ct)
+	if err != nil {
+		return "", fmt.Errorf("error - type %s: %w", name, err)
+	}
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		// The same element can appear in several branches of a choice.
+		if !seen[f.Name] {
+			seen[f.Name] = true
+			s.Fields = append(s.Fields, f)
+		}
+	}
+	return name, nil
+}
+
+// fields returns the fields of the given complex type, including those of its base type.
+func (g *generator) fields(ct *xsdComplexType) ([]field, error) {
+	var fields []field
+	if ct.Base != "" {
+		base, found := g.complexTypes[localName(ct.Base)]
+		if !found {
+			return nil, fmt.Errorf("unknown base type %s", ct.Base)
+		}
+		baseFields, err := g.fields(base)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, baseFields...)
+	}
+	groupFields, err := g.groupFields(&ct.Content, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(fields, groupFields...), nil
+}
+
+// groupFields returns the fields for the elements of the given model group. Elements of an
+// optional group or of a choice are optional.
+func (g *generator) groupFields(group *xsdGroup, repeated, optional bool) ([]field, error) {
+	repeated = repeated || isMany(group.MaxOccurs)
+	optional = optional || group.MinOccurs == "0" || group.Kind == "choice"
+	var fields []field
+	for _, p := range group.Particles {
+		if p.Group != nil {
+			nested, err := g.groupFields(p.Group, repeated, optional)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+		e, tag := p.Element, p.Element.Name
+		if e.Ref != "" {
+			tag = localName(e.Ref)
+			global, found := g.elements[tag]
+			if !found {
+				return nil, fmt.Errorf("unknown element %s", e.Ref)
+			}
+			e = global
+		}
+		typ, err := g.elementType(tag, e)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case repeated || isMany(p.Element.MaxOccurs):
+			typ = "[]" + typ
+		case g.isAggregate(e) && (optional || p.Element.MinOccurs == "0"):
+			// Optional aggregates are pointers, to tell whether they are present.
+			typ = "*" + typ
+		}
+		fields = append(fields, field{Name: goName(tag), Type: typ, Tag: tag})
+	}
+	return fields, nil
+}
+
+// elementType returns the Go type of the given element with the given tag, and records the
+// tag as an aggregate or element.
+func (g *generator) elementType(tag string, e *xsdElement) (string, error) {
+	g.tags[tag] = struct{}{}
+	switch {
+	case e.ComplexType != nil:
+		g.aggregates[tag] = struct{}{}
+		return g.addStruct(goName(tag), e.ComplexType)
+	case e.SimpleType != nil:
+		return g.simpleType(e.SimpleType)
+	case e.Type == "":
+		return "string", nil
+	}
+	name := localName(e.Type)
+	if _, found := g.complexTypes[name]; found {
+		g.aggregates[tag] = struct{}{}
+		return exportName(name), nil
+	}
+	return g.typeName(name)
+}
+
+// isAggregate returns true if the given element has a complex type.
+func (g *generator) isAggregate(e *xsdElement) bool {
+	_, found := g.complexTypes[localName(e.Type)]
+	return e.ComplexType != nil || (e.Type != "" && found)
+}
+
+// typeName returns the Go type for the given simple or built-in XSD type.
+func (g *generator) typeName(name string) (string, error) {
+	if st, found := g.simpleTypes[name]; found {
+		return g.simpleType(st)
+	}
+	if typ, found := builtinTypes[name]; found {
+		return typ, nil
+	}
+	return "", fmt.Errorf("unknown type %s", name)
+}
+
+// simpleType returns the Go type for the given simple type, its own type if it is an
+// enumeration, otherwise the type of its base.
+func (g *generator) simpleType(st *xsdSimpleType) (string, error) {
+	if st.Name != "" && len(st.Restriction.Enumerations) > 0 {
+		return exportName(st.Name), nil
+	}
+	return g.typeName(localName(st.Restriction.Base))
+}
+
+// isMany returns true if the given maxOccurs allows more than one occurrence.
+func isMany(maxOccurs string) bool {
+	return maxOccurs != "" && maxOccurs != "0" && maxOccurs != "1"
+}
+
+// goName returns an exported Go name for the given tag or value, e.g. INTU.BID becomes IntuBid.
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// exportName returns the given type name with its first letter in upper case.
+func exportName(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// writeDoc writes the doc comment for the type with the given name.
+func writeDoc(buf *bytes.Buffer, name, doc string) {
+	doc = strings.Join(strings.Fields(doc), " ")
+	if doc == "" {
+		doc = "is generated from the OFX schema."
+	}
+	fmt.Fprintf(buf, "// %s %s\n", name, doc)
+}
+
+// sortedKeys returns the keys of the given set in order.
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// quoteAll returns the given strings quoted and comma separated.
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
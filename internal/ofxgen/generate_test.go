@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ofxgen", func() {
+	Describe("generateFiles()", func() {
+		It("should generate the types, enums and tag tables", func() {
+			source, err := generateFiles([]string{"testdata/sample.xsd"}, "spec", "220")
+			Expect(err).To(BeNil())
+			golden, err := ioutil.ReadFile("testdata/sample.golden")
+			Expect(err).To(BeNil())
+			Expect(string(source)).To(Equal(string(golden)))
+		})
+	})
+	Describe("run()", func() {
+		It("should fail when there are no XSD files", func() {
+			Expect(run("testdata/missing", "", "spec", "")).NotTo(Succeed())
+		})
+	})
+	DescribeTable("goName()", func(s, expected string) {
+		Expect(goName(s)).To(Equal(expected))
+	},
+		Entry("tag", "STMTTRN", "Stmttrn"),
+		Entry("extension tag", "INTU.BID", "IntuBid"),
+		Entry("value with underscore", "OPEN_END", "OpenEnd"),
+	)
+})
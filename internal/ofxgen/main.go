@@ -0,0 +1,67 @@
+// Command ofxgen generates Go types from the XSD schemas of the OFX spec.
+//
+// It emits a struct for each complex type, a string type with constants for each enumerated
+// simple type, and registers the aggregate and element tags of the schemas for the cleaner.
+//
+//	ofxgen -xsd xsd -out types.go -package spec -version 220
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	var (
+		dir     = flag.String("xsd", "xsd", "directory holding the XSD files")
+		out     = flag.String("out", "types.go", "file to write the generated types to")
+		pkg     = flag.String("package", "spec", "package of the generated types")
+		version = flag.String("version", "", "VERSION of the spec the XSD files define e.g. 220")
+	)
+	flag.Parse()
+	if err := run(*dir, *out, *pkg, *version); err != nil {
+		fmt.Fprintf(os.Stderr, "ofxgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the types for the XSD files in the given directory into the given file.
+func run(dir, out, pkg, version string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.xsd"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("error - no XSD files found in %s, see %s", dir, filepath.Join(dir, "README.md"))
+	}
+	sort.Strings(files)
+	source, err := generateFiles(files, pkg, version)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, source, 0644)
+}
+
+// generateFiles returns the generated Go source for the given XSD files.
+func generateFiles(files []string, pkg, version string) ([]byte, error) {
+	schemas := make([]*xsdSchema, 0, len(files))
+	sources := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var schema xsdSchema
+		if err := xml.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("error - parsing %s: %w", file, err)
+		}
+		schemas = append(schemas, &schema)
+		sources = append(sources, filepath.Base(file))
+	}
+	return newGenerator(pkg, version, schemas).generate(sources)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// xsdSchema is an XML schema document. Only the parts of XSD used by the OFX schemas are
+// supported.
+type xsdSchema struct {
+	Elements     []xsdElement     `xml:"element"`
+	ComplexTypes []xsdComplexType `xml:"complexType"`
+	SimpleTypes  []xsdSimpleType  `xml:"simpleType"`
+}
+
+// xsdElement is an element declaration or reference.
+type xsdElement struct {
+	Name        string          `xml:"name,attr"`
+	Type        string          `xml:"type,attr"`
+	Ref         string          `xml:"ref,attr"`
+	MinOccurs   string          `xml:"minOccurs,attr"`
+	MaxOccurs   string          `xml:"maxOccurs,attr"`
+	ComplexType *xsdComplexType `xml:"complexType"`
+	SimpleType  *xsdSimpleType  `xml:"simpleType"`
+}
+
+// xsdComplexType is a complex type definition.
+type xsdComplexType struct {
+	Name          string
+	Documentation string
+	Base          string // Name of the base type, if this type is an extension.
+	Content       xsdGroup
+}
+
+// xsdSimpleType is a simple type definition.
+type xsdSimpleType struct {
+	Name          string `xml:"name,attr"`
+	Documentation string `xml:"annotation>documentation"`
+	Restriction   struct {
+		Base         string `xml:"base,attr"`
+		Enumerations []struct {
+			Value string `xml:"value,attr"`
+		} `xml:"enumeration"`
+	} `xml:"restriction"`
+}
+
+// xsdParticle is an element or a nested group of a model group.
+type xsdParticle struct {
+	Element *xsdElement
+	Group   *xsdGroup
+}
+
+// xsdGroup is a sequence, choice or all model group, keeping its particles in order.
+type xsdGroup struct {
+	Kind      string // sequence, choice or all.
+	MinOccurs string
+	MaxOccurs string
+	Particles []xsdParticle
+}
+
+// UnmarshalXML decodes a complex type, flattening complexContent extensions.
+func (t *xsdComplexType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	t.Name = attr(start, "name")
+	var decode func(child xml.StartElement) error
+	decode = func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "annotation":
+			var a struct {
+				Documentation string `xml:"documentation"`
+			}
+			if err := d.DecodeElement(&a, &child); err != nil {
+				return err
+			}
+			t.Documentation = strings.TrimSpace(a.Documentation)
+			return nil
+		case "sequence", "choice", "all":
+			return d.DecodeElement(&t.Content, &child)
+		case "complexContent":
+			return decodeChildren(d, decode)
+		case "extension":
+			t.Base = attr(child, "base")
+			return decodeChildren(d, decode)
+		}
+		return d.Skip()
+	}
+	return decodeChildren(d, decode)
+}
+
+// UnmarshalXML decodes a model group and its particles in order.
+func (g *xsdGroup) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	g.Kind = start.Name.Local
+	g.MinOccurs = attr(start, "minOccurs")
+	g.MaxOccurs = attr(start, "maxOccurs")
+	return decodeChildren(d, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "element":
+			var e xsdElement
+			if err := d.DecodeElement(&e, &child); err != nil {
+				return err
+			}
+			g.Particles = append(g.Particles, xsdParticle{Element: &e})
+			return nil
+		case "sequence", "choice", "all":
+			var nested xsdGroup
+			if err := d.DecodeElement(&nested, &child); err != nil {
+				return err
+			}
+			g.Particles = append(g.Particles, xsdParticle{Group: &nested})
+			return nil
+		}
+		return d.Skip()
+	})
+}
+
+// decodeChildren calls fn for each child element of the element being decoded, which must
+// consume the child.
+func decodeChildren(d *xml.Decoder, fn func(xml.StartElement) error) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := fn(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// attr returns the value of the attribute with the given local name.
+func attr(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// localName strips the namespace prefix from the given qualified name.
+func localName(name string) string {
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,105 @@
+package goofx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// Errors returned when the input exceeds one of the configured Limits.
+var (
+	ErrInputTooLarge       = errors.New("error - input exceeds maximum size")
+	ErrNestingTooDeep      = errors.New("error - aggregates exceed maximum nesting depth")
+	ErrDataTooLong         = errors.New("error - element data exceeds maximum length")
+	ErrTooManyTransactions = errors.New("error - transactions exceed maximum count")
+)
+
+// Limits bounds the resources used while parsing untrusted input.
+// A zero value for any field disables that limit.
+type Limits struct {
+	MaxBytes        int64 // Maximum size of the raw input in bytes as it is read, before conversion to UTF-8 and preprocessing.
+	MaxDepth        int   // Maximum nesting depth of aggregates.
+	MaxDataLength   int   // Maximum length of the char data of a single element.
+	MaxTransactions int   // Maximum number of STMTTRN aggregates.
+}
+
+// Limiter is implemented by cleaners that bound the resources used while parsing.
+type Limiter interface {
+	Limits() Limits
+}
+
+// readData reads all data from the given reader converted to UTF-8, failing once more than
+// limits.MaxBytes have been read from it.
+func readData(reader io.Reader, limits Limits) ([]byte, error) {
+	if limits.MaxBytes <= 0 {
+		return ioutil.ReadAll(newUTF8Reader(reader))
+	}
+	counter := &countingReader{reader: io.LimitReader(reader, limits.MaxBytes+1)}
+	data, err := ioutil.ReadAll(newUTF8Reader(counter))
+	if err != nil {
+		return nil, err
+	}
+	if counter.count > limits.MaxBytes {
+		return nil, ErrInputTooLarge
+	}
+	return data, nil
+}
+
+// countingReader is an io.Reader counting the bytes read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+// Read reads from the underlying reader, adding the bytes read to the count.
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// isLimitError returns true if the given error is caused by exceeding a limit.
+func isLimitError(err error) bool {
+	return err == ErrInputTooLarge || err == ErrNestingTooDeep ||
+		err == ErrDataTooLong || err == ErrTooManyTransactions
+}
+
+// limitTokenReader is an xml.TokenReader that enforces limits on the tokens read
+// from the underlying reader.
+type limitTokenReader struct {
+	reader   xml.TokenReader
+	limits   Limits
+	depth    int
+	txnCount int
+}
+
+// Token returns the next token from the underlying reader, or an error if it exceeds a limit.
+func (r *limitTokenReader) Token() (xml.Token, error) {
+	token, err := r.reader.Token()
+	if err != nil {
+		return token, err
+	}
+	switch t := token.(type) {
+	case xml.StartElement:
+		// Elements add a level of nesting within the innermost aggregate.
+		r.depth++
+		if r.limits.MaxDepth > 0 && r.depth > r.limits.MaxDepth+1 {
+			return nil, ErrNestingTooDeep
+		}
+		if t.Name.Local == "STMTTRN" {
+			r.txnCount++
+			if r.limits.MaxTransactions > 0 && r.txnCount > r.limits.MaxTransactions {
+				return nil, ErrTooManyTransactions
+			}
+		}
+	case xml.EndElement:
+		r.depth--
+	case xml.CharData:
+		if r.limits.MaxDataLength > 0 && len(bytes.TrimSpace(t)) > r.limits.MaxDataLength {
+			return nil, ErrDataTooLong
+		}
+	}
+	return token, nil
+}
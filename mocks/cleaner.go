@@ -6,6 +6,7 @@ package mocks
 
 import (
 	bytes "bytes"
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -61,3 +62,70 @@ func (mr *MockOFXCleanerMockRecorder) CleanupXML() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupXML", reflect.TypeOf((*MockOFXCleaner)(nil).CleanupXML))
 }
+
+// MockContextCleaner is a mock of ContextCleaner interface
+type MockContextCleaner struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextCleanerMockRecorder
+}
+
+// MockContextCleanerMockRecorder is the mock recorder for MockContextCleaner
+type MockContextCleanerMockRecorder struct {
+	mock *MockContextCleaner
+}
+
+// NewMockContextCleaner creates a new mock instance
+func NewMockContextCleaner(ctrl *gomock.Controller) *MockContextCleaner {
+	mock := &MockContextCleaner{ctrl: ctrl}
+	mock.recorder = &MockContextCleanerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockContextCleaner) EXPECT() *MockContextCleanerMockRecorder {
+	return m.recorder
+}
+
+// Init mocks base method
+func (m *MockContextCleaner) Init(arg0 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init
+func (mr *MockContextCleanerMockRecorder) Init(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockContextCleaner)(nil).Init), arg0)
+}
+
+// CleanupXML mocks base method
+func (m *MockContextCleaner) CleanupXML() (*bytes.Buffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanupXML")
+	ret0, _ := ret[0].(*bytes.Buffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CleanupXML indicates an expected call of CleanupXML
+func (mr *MockContextCleanerMockRecorder) CleanupXML() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupXML", reflect.TypeOf((*MockContextCleaner)(nil).CleanupXML))
+}
+
+// CleanupXMLContext mocks base method
+func (m *MockContextCleaner) CleanupXMLContext(arg0 context.Context) (*bytes.Buffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanupXMLContext", arg0)
+	ret0, _ := ret[0].(*bytes.Buffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CleanupXMLContext indicates an expected call of CleanupXMLContext
+func (mr *MockContextCleanerMockRecorder) CleanupXMLContext(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupXMLContext", reflect.TypeOf((*MockContextCleaner)(nil).CleanupXMLContext), arg0)
+}
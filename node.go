@@ -0,0 +1,274 @@
+package goofx
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Node is a node of an OFX tree. Aggregates have ordered children, elements have text.
+type Node struct {
+	Name      string
+	Text      string // Data of an element, empty for aggregates.
+	Aggregate bool
+	Children  []*Node
+	Parent    *Node
+}
+
+// NewElement returns an element node with the given name and text.
+func NewElement(name, text string) *Node {
+	return &Node{Name: name, Text: text}
+}
+
+// NewAggregate returns an aggregate node with the given name and children.
+func NewAggregate(name string, children ...*Node) *Node {
+	n := &Node{Name: name, Aggregate: true}
+	for _, child := range children {
+		n.AppendChild(child)
+	}
+	return n
+}
+
+// NewTree parses the given file into a tree of nodes and returns its root OFX aggregate. The
+// input is cleaned as for NewDocumentFromXML.
+func NewTree(reader io.Reader, cleaner Cleaner, opts ...ParseOption) (*Node, error) {
+	return NewTreeContext(context.Background(), reader, cleaner, opts...)
+}
+
+// NewTreeContext is like NewTree but stops parsing and returns ctx.Err() once the given
+// context is done.
+func NewTreeContext(ctx context.Context, reader io.Reader, cleaner Cleaner, opts ...ParseOption) (*Node, error) {
+	var limits Limits
+	if l, ok := cleaner.(Limiter); ok {
+		limits = l.Limits()
+	}
+	data, err := readData(&contextReader{ctx: ctx, reader: reader}, limits)
+	if err != nil {
+		return nil, err
+	}
+	header := ParseHeader(data)
+	data, cleaner, _, err = prepareData(data, cleaner, newParseConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	schema := SchemaForVersion(header.Version)
+	var root *Node
+	_, _, err = decodeData(ctx, data, header, cleaner, limits, func(reader xml.TokenReader, _ ParsePath) error {
+		var buildErr error
+		root, buildErr = buildTree(reader, schema)
+		return buildErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// buildTree builds a tree from the tokens of the given reader. Nodes with children, and
+// empty nodes the given schema defines as aggregates, are aggregates.
+func buildTree(reader xml.TokenReader, schema *Schema) (*Node, error) {
+	var (
+		root    *Node
+		current *Node
+	)
+	for {
+		token, err := reader.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &Node{Name: t.Name.Local, Aggregate: schema.IsAggregate(t.Name.Local)}
+			if current == nil {
+				if root != nil {
+					return nil, errors.New("error - multiple root elements")
+				}
+				root = n
+			} else {
+				current.AppendChild(n)
+				current.Aggregate = true
+				current.Text = ""
+			}
+			current = n
+		case xml.CharData:
+			if current != nil && !current.Aggregate {
+				current.Text += string(t)
+			}
+		case xml.EndElement:
+			if current == nil {
+				return nil, errors.New("error - unexpected end element " + t.Name.Local)
+			}
+			current.Text = strings.TrimSpace(current.Text)
+			current = current.Parent
+		}
+	}
+	if root == nil {
+		return nil, errors.New("error - invalid file, OFX tag not found")
+	}
+	return root, nil
+}
+
+// Child returns the first child with the given name, or nil if there is none.
+func (n *Node) Child(name string) *Node {
+	name = NormalizeTag(name)
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// ChildrenNamed returns the children with the given name, in order.
+func (n *Node) ChildrenNamed(name string) []*Node {
+	name = NormalizeTag(name)
+	var children []*Node
+	for _, child := range n.Children {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Value returns the text of the first child element with the given name.
+func (n *Node) Value(name string) (string, bool) {
+	if child := n.Child(name); child != nil && !child.Aggregate {
+		return child.Text, true
+	}
+	return "", false
+}
+
+// AppendChild adds the given node as the last child of this node, removing it from its
+// previous parent.
+func (n *Node) AppendChild(child *Node) {
+	n.InsertChild(len(n.Children), child)
+}
+
+// InsertChild inserts the given node as the i-th child of this node, removing it from its
+// previous parent.
+func (n *Node) InsertChild(i int, child *Node) {
+	if child.Parent != nil {
+		child.Parent.RemoveChild(child)
+	}
+	if i > len(n.Children) {
+		i = len(n.Children)
+	}
+	n.Children = append(n.Children, nil)
+	copy(n.Children[i+1:], n.Children[i:])
+	n.Children[i] = child
+	child.Parent = n
+}
+
+// RemoveChild removes the given child from this node, returning false if it isn't a child.
+func (n *Node) RemoveChild(child *Node) bool {
+	for i, c := range n.Children {
+		if c == child {
+			n.Children = append(n.Children[:i], n.Children[i+1:]...)
+			child.Parent = nil
+			return true
+		}
+	}
+	return false
+}
+
+// Walk calls fn for this node and its descendants in document order. Children of a node are
+// skipped if fn returns false for it.
+func (n *Node) Walk(fn func(*Node) bool) {
+	if !fn(n) {
+		return
+	}
+	// Copy the children so fn can modify them.
+	for _, child := range append([]*Node{}, n.Children...) {
+		child.Walk(fn)
+	}
+}
+
+// Decode decodes this node into v, which must be a non-nil pointer, e.g. into a Document
+// after fixing up the tree.
+//
+// Struct fields are mapped using their ofx tag, or their xml tag if they have none, with the
+// same syntax as encoding/xml: a name or a path such as STATUS>CODE, "-" to skip the field,
+// ",any" for unmapped children and ",chardata" for the text of the node. Fields without a
+// tag are mapped by their name. Names are matched case-insensitively. Slices collect every
+// match, time.Time fields are parsed with ParseDate, bool fields accept Y and N, and types
+// implementing xml.Unmarshaler or encoding.TextUnmarshaler decode themselves.
+func (n *Node) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("error - decode requires a non-nil pointer")
+	}
+	return decodeNode(n, rv.Elem())
+}
+
+// nodeTokenReader is an xml.TokenReader returning the tokens of a node and its descendants.
+type nodeTokenReader struct {
+	tokens []xml.Token
+}
+
+// newNodeTokenReader returns a token reader for the given node.
+func newNodeTokenReader(n *Node) *nodeTokenReader {
+	r := &nodeTokenReader{}
+	r.add(n)
+	return r
+}
+
+// add appends the tokens of the given node.
+func (r *nodeTokenReader) add(n *Node) {
+	name := xml.Name{Local: n.Name}
+	r.tokens = append(r.tokens, xml.StartElement{Name: name})
+	if n.Aggregate {
+		for _, child := range n.Children {
+			r.add(child)
+		}
+	} else if n.Text != "" {
+		r.tokens = append(r.tokens, xml.CharData(n.Text))
+	}
+	r.tokens = append(r.tokens, xml.EndElement{Name: name})
+}
+
+// Token returns the next token of the node.
+func (r *nodeTokenReader) Token() (xml.Token, error) {
+	if len(r.tokens) == 0 {
+		return nil, io.EOF
+	}
+	token := r.tokens[0]
+	r.tokens = r.tokens[1:]
+	return token, nil
+}
+
+// WriteTo writes this node as XML to the given writer.
+func (n *Node) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	n.write(&buf)
+	written, err := w.Write(buf.Bytes())
+	return int64(written), err
+}
+
+// write writes this node as XML to the given buffer.
+func (n *Node) write(buf *bytes.Buffer) {
+	name := xml.Name{Local: n.Name}
+	writeStartTag(&xml.StartElement{Name: name}, buf)
+	if n.Aggregate {
+		for _, child := range n.Children {
+			child.write(buf)
+		}
+	} else {
+		buf.WriteString(EscapeString(n.Text))
+	}
+	writeEndTag(name, buf)
+}
+
+// String returns this node as XML.
+func (n *Node) String() string {
+	var buf bytes.Buffer
+	n.write(&buf)
+	return buf.String()
+}
@@ -0,0 +1,96 @@
+package goofx_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("NewTree()", func() {
+		data := "OFXHEADER:100\nVERSION:102\n\n<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS>" +
+			"<FI><ORG>Test &amp; Bank<FID>123</FI></SONRS></SIGNONMSGSRSV1><BANKMSGSRSV1><STMTTRNRS><STMTRS>" +
+			"<BANKTRANLIST></BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+
+		It("should build a tree from the cleaned data", func() {
+			root, err := goofx.NewTree(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(root.Name).To(Equal("OFX"))
+			Expect(root.Aggregate).To(BeTrue())
+			Expect(root.Parent).To(BeNil())
+			sonrs := root.Child("SIGNONMSGSRSV1").Child("sonrs")
+			Expect(sonrs.Parent.Name).To(Equal("SIGNONMSGSRSV1"))
+			Expect(sonrs.Child("STATUS").Child("CODE").Text).To(Equal("0"))
+			org, found := sonrs.Child("FI").Value("ORG")
+			Expect(found).To(BeTrue())
+			Expect(org).To(Equal("Test & Bank"))
+			tranList := root.Child("BANKMSGSRSV1").Child("STMTTRNRS").Child("STMTRS").Child("BANKTRANLIST")
+			Expect(tranList.Aggregate).To(BeTrue())
+			Expect(tranList.Children).To(BeEmpty())
+		})
+		It("should build a tree from well-formed data", func() {
+			root, err := goofx.NewTree(strings.NewReader("<OFX><A><B>1</B><B>2</B></A></OFX>"), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			b := root.Child("A").ChildrenNamed("B")
+			Expect(b).To(HaveLen(2))
+			Expect(b[1].Text).To(Equal("2"))
+		})
+		It("should stop once the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := goofx.NewTreeContext(ctx, strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+	Describe("Node", func() {
+		var root *goofx.Node
+		BeforeEach(func() {
+			root = goofx.NewAggregate("OFX",
+				goofx.NewAggregate("STMTTRN",
+					goofx.NewElement("TRNAMT", "-1.00"),
+					goofx.NewElement("NAME", "A<B"),
+				),
+			)
+		})
+
+		It("should serialize to XML", func() {
+			Expect(root.String()).To(Equal("<OFX><STMTTRN><TRNAMT>-1.00</TRNAMT><NAME>A&lt;B</NAME></STMTTRN></OFX>"))
+			var buf bytes.Buffer
+			_, err := root.WriteTo(&buf)
+			Expect(err).To(BeNil())
+			Expect(buf.String()).To(Equal(root.String()))
+		})
+		It("should insert, move and remove children", func() {
+			txn := root.Child("STMTTRN")
+			memo := goofx.NewElement("MEMO", "memo")
+			txn.InsertChild(1, memo)
+			Expect(txn.String()).To(Equal("<STMTTRN><TRNAMT>-1.00</TRNAMT><MEMO>memo</MEMO><NAME>A&lt;B</NAME></STMTTRN>"))
+			root.AppendChild(memo)
+			Expect(memo.Parent).To(Equal(root))
+			Expect(txn.Child("MEMO")).To(BeNil())
+			Expect(root.RemoveChild(memo)).To(BeTrue())
+			Expect(root.RemoveChild(memo)).To(BeFalse())
+			Expect(memo.Parent).To(BeNil())
+		})
+		It("should walk the tree in document order", func() {
+			var names []string
+			root.Walk(func(n *goofx.Node) bool {
+				names = append(names, n.Name)
+				return n.Name != "STMTTRN"
+			})
+			Expect(names).To(Equal([]string{"OFX", "STMTTRN"}))
+		})
+		It("should decode into a Document", func() {
+			tree := goofx.NewAggregate("OFX", goofx.NewAggregate("BANKMSGSRSV1", goofx.NewAggregate("STMTTRNRS",
+				goofx.NewAggregate("STMTRS", goofx.NewAggregate("BANKTRANLIST", root.Child("STMTTRN"))))))
+			var d goofx.Document
+			Expect(tree.Decode(&d)).To(Succeed())
+			Expect(d.BRMS[0].TRS.RS.Transactions[0].Name).To(Equal("A<B"))
+		})
+	})
+})
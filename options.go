@@ -0,0 +1,40 @@
+package goofx
+
+// ParseOption configures how NewDocumentFromXML and DocumentScanner parse OFX data.
+type ParseOption func(*parseConfig)
+
+// parseConfig holds the configuration set by ParseOptions.
+type parseConfig struct {
+	preprocessors []Preprocessor
+	disabled      map[string]bool
+	quirks        *QuirkRegistry
+}
+
+// newParseConfig returns the configuration set by the given options.
+func newParseConfig(opts []ParseOption) *parseConfig {
+	config := &parseConfig{
+		preprocessors: DefaultPreprocessors(),
+		disabled:      make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// WithPreprocessors registers the given preprocessors. Preprocessors are applied in the order
+// they are registered, after the built-in DefaultPreprocessors.
+func WithPreprocessors(preprocessors ...Preprocessor) ParseOption {
+	return func(c *parseConfig) {
+		c.preprocessors = append(c.preprocessors, preprocessors...)
+	}
+}
+
+// WithoutPreprocessors disables the preprocessors with the given names, including built-in ones.
+func WithoutPreprocessors(names ...string) ParseOption {
+	return func(c *parseConfig) {
+		for _, name := range names {
+			c.disabled[name] = true
+		}
+	}
+}
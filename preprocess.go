@@ -1,11 +1,71 @@
 package goofx
 
-import "regexp"
-
-// preprocessOFXData applies one-off transforms to fix bad data.
-// This should not be required as the library matures.
-func preprocessOFXData(content []byte) []byte {
-	from := regexp.MustCompile(`(</CURDEF>\s+)(<BANKID>)`)
-	content = from.ReplaceAll(content, []byte("$1<BANKACCTFROM>$2"))
-	return content
+import (
+	"fmt"
+	"regexp"
+)
+
+// BankAccountFromFixName is the name of the built-in BankAccountFromFix preprocessor.
+const BankAccountFromFixName = "bankacctfrom"
+
+// bankAccountFromPattern matches bank account elements following CURDEF without their
+// enclosing BANKACCTFROM aggregate.
+var bankAccountFromPattern = regexp.MustCompile(`(</CURDEF>\s+)(<BANKID>)`)
+
+// Preprocessor applies a byte level fix to raw OFX data before it is decoded, e.g. to work
+// around bank specific deviations from the spec.
+type Preprocessor interface {
+	// Name identifies the preprocessor, e.g. to disable it with WithoutPreprocessors.
+	Name() string
+	// Process returns the fixed data.
+	Process([]byte) ([]byte, error)
+}
+
+// preprocessorFunc is a Preprocessor implemented by a function.
+type preprocessorFunc struct {
+	name string
+	fn   func([]byte) ([]byte, error)
+}
+
+// NewPreprocessor returns a Preprocessor with the given name that applies the given function.
+func NewPreprocessor(name string, fn func([]byte) ([]byte, error)) Preprocessor {
+	return &preprocessorFunc{name: name, fn: fn}
+}
+
+// Name returns the name of this preprocessor.
+func (p *preprocessorFunc) Name() string {
+	return p.name
+}
+
+// Process applies this preprocessor to the given data.
+func (p *preprocessorFunc) Process(data []byte) ([]byte, error) {
+	return p.fn(data)
+}
+
+// BankAccountFromFix returns the built-in preprocessor that inserts a missing BANKACCTFROM
+// start tag before the BANKID following CURDEF.
+func BankAccountFromFix() Preprocessor {
+	return NewPreprocessor(BankAccountFromFixName, func(data []byte) ([]byte, error) {
+		return bankAccountFromPattern.ReplaceAll(data, []byte("$1<BANKACCTFROM>$2")), nil
+	})
+}
+
+// DefaultPreprocessors returns the built-in preprocessors, in the order they are applied.
+func DefaultPreprocessors() []Preprocessor {
+	return []Preprocessor{BankAccountFromFix()}
+}
+
+// preprocess applies the given preprocessors, in order, to the given data, skipping any
+// that are disabled.
+func preprocess(data []byte, preprocessors []Preprocessor, disabled map[string]bool) ([]byte, error) {
+	for _, p := range preprocessors {
+		if disabled[p.Name()] {
+			continue
+		}
+		var err error
+		if data, err = p.Process(data); err != nil {
+			return nil, fmt.Errorf("error - preprocessor %s failed: %w", p.Name(), err)
+		}
+	}
+	return data, nil
 }
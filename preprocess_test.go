@@ -0,0 +1,73 @@
+package goofx_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("BankAccountFromFix()", func() {
+		It("should insert the missing BANKACCTFROM start tag", func() {
+			p := goofx.BankAccountFromFix()
+			Expect(p.Name()).To(Equal(goofx.BankAccountFromFixName))
+			data, err := p.Process([]byte("<CURDEF>USD</CURDEF>\n<BANKID>1"))
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal("<CURDEF>USD</CURDEF>\n<BANKACCTFROM><BANKID>1"))
+		})
+	})
+	Describe("NewDocumentFromXML() with preprocessors", func() {
+		data := "<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD</CURDEF>\n" +
+			"<BANKID>456<ACCTID>789</BANKACCTFROM></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+		replace := func(name, old, new string) goofx.Preprocessor {
+			return goofx.NewPreprocessor(name, func(data []byte) ([]byte, error) {
+				return bytes.Replace(data, []byte(old), []byte(new), -1), nil
+			})
+		}
+
+		Context("when using the default preprocessors", func() {
+			It("should apply the built-in fixes", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(d.BRMS[0].TRS.RS.BankID).To(Equal("456"))
+			})
+		})
+		Context("when a built-in preprocessor is disabled", func() {
+			It("should not apply it", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner(),
+					goofx.WithoutPreprocessors(goofx.BankAccountFromFixName))
+				Expect(err).To(BeNil())
+				Expect(d.BRMS[0].TRS.RS.BankID).To(BeEmpty())
+			})
+		})
+		Context("when custom preprocessors are registered", func() {
+			It("should apply them in order after the built-in ones", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner(),
+					goofx.WithPreprocessors(
+						replace("first", "<BANKACCTFROM><BANKID>456", "<BANKACCTFROM><BANKID>123"),
+						replace("second", "123", "321"),
+						replace("disabled", "321", "000"),
+					),
+					goofx.WithoutPreprocessors("disabled"))
+				Expect(err).To(BeNil())
+				Expect(d.BRMS[0].TRS.RS.BankID).To(Equal("321"))
+			})
+		})
+		Context("when a preprocessor fails", func() {
+			It("should return an error", func() {
+				failing := goofx.NewPreprocessor("failing", func([]byte) ([]byte, error) {
+					return nil, errors.New("test error")
+				})
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner(),
+					goofx.WithPreprocessors(failing))
+				Expect(err).To(MatchError("error - preprocessor failing failed: test error"))
+				Expect(d).To(BeNil())
+			})
+		})
+	})
+})
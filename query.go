@@ -0,0 +1,324 @@
+package goofx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query is a compiled path query over a node tree, e.g.
+//
+//	BANKMSGSRSV1/STMTTRNRS/STMTRS/BANKTRANLIST/STMTTRN[TRNTYPE=FEE]/TRNAMT
+//
+// Steps are separated by '/' and match children by name, or any child for '*'. A step after
+// '//' matches descendants at any depth. A leading '/' starts at the root node itself instead
+// of its children. Each step can have predicates:
+//
+//	[NAME]        has a child named NAME
+//	[NAME=value]  has a child element NAME with the given text, != for a different text
+//	[.=value]     the node's own text is value
+//	[2]           the second match of the step for each parent, starting at 1
+//
+// Values may be quoted with ' or ". Names are matched case-insensitively.
+type Query struct {
+	path     string
+	absolute bool
+	steps    []queryStep
+}
+
+// queryStep is a step of a query.
+type queryStep struct {
+	name       string // Normalized name, * for any.
+	descendant bool
+	predicates []queryPredicate
+}
+
+// queryPredicate is a predicate of a query step.
+type queryPredicate struct {
+	name  string // Normalized name of the child to test, . for the node itself.
+	op    string // Empty for existence tests, = or != otherwise.
+	value string
+	index int // Set for index predicates.
+}
+
+// CompileQuery parses the given path into a query.
+func CompileQuery(path string) (*Query, error) {
+	q := &Query{path: path}
+	rest := strings.TrimSpace(path)
+	descendant := false
+	if strings.HasPrefix(rest, "//") {
+		descendant, rest = true, rest[2:]
+	} else if strings.HasPrefix(rest, "/") {
+		q.absolute, rest = true, rest[1:]
+	}
+	for _, part := range splitQuery(rest) {
+		if part == "" {
+			if descendant {
+				return nil, fmt.Errorf("error - invalid query %q, empty step", path)
+			}
+			descendant = true
+			continue
+		}
+		step, err := parseStep(part)
+		if err != nil {
+			return nil, fmt.Errorf("error - invalid query %q, %s", path, err)
+		}
+		step.descendant = descendant
+		descendant = false
+		q.steps = append(q.steps, step)
+	}
+	if len(q.steps) == 0 || descendant {
+		return nil, fmt.Errorf("error - invalid query %q, missing step", path)
+	}
+	return q, nil
+}
+
+// MustCompileQuery is like CompileQuery but panics if the path can't be parsed.
+func MustCompileQuery(path string) *Query {
+	q, err := CompileQuery(path)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// String returns the path of this query.
+func (q *Query) String() string {
+	return q.path
+}
+
+// splitQuery splits the given path on '/' outside of predicates and quotes.
+func splitQuery(path string) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '/' && depth == 0:
+			parts = append(parts, path[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, path[start:])
+}
+
+// parseStep parses a single step with its predicates.
+func parseStep(s string) (queryStep, error) {
+	var step queryStep
+	i := strings.IndexByte(s, '[')
+	if i == -1 {
+		i = len(s)
+	}
+	step.name = NormalizeTag(strings.TrimSpace(s[:i]))
+	if step.name == "" {
+		return step, fmt.Errorf("missing name in step %q", s)
+	}
+	for rest := s[i:]; rest != ""; {
+		end := predicateEnd(rest)
+		if rest[0] != '[' || end == -1 {
+			return step, fmt.Errorf("invalid predicate in step %q", s)
+		}
+		p, err := parsePredicate(rest[1:end])
+		if err != nil {
+			return step, err
+		}
+		step.predicates = append(step.predicates, p)
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	return step, nil
+}
+
+// predicateEnd returns the index of the ']' closing the predicate at the start of s.
+func predicateEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePredicate parses the contents of a predicate.
+func parsePredicate(s string) (queryPredicate, error) {
+	s = strings.TrimSpace(s)
+	if index, err := strconv.Atoi(s); err == nil {
+		if index < 1 {
+			return queryPredicate{}, fmt.Errorf("invalid index %d", index)
+		}
+		return queryPredicate{index: index}, nil
+	}
+	p := queryPredicate{name: s}
+	for _, op := range []string{"!=", "="} {
+		if i := strings.Index(s, op); i != -1 {
+			p.name, p.op, p.value = strings.TrimSpace(s[:i]), op, unquote(strings.TrimSpace(s[i+len(op):]))
+			break
+		}
+	}
+	if p.name == "" {
+		return p, fmt.Errorf("missing name in predicate %q", s)
+	}
+	if p.name != "." {
+		p.name = NormalizeTag(p.name)
+	}
+	return p, nil
+}
+
+// unquote strips matching quotes around the given value.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Nodes returns the nodes matching this query relative to the given node, in document order.
+func (q *Query) Nodes(n *Node) []*Node {
+	context := []*Node{n}
+	for i, step := range q.steps {
+		var next []*Node
+		seen := make(map[*Node]bool)
+		for _, node := range context {
+			// Index predicates count the matches among the children of each parent.
+			var groups [][]*Node
+			switch {
+			case i == 0 && q.absolute:
+				groups = [][]*Node{{node}}
+			case step.descendant:
+				node.Walk(func(d *Node) bool {
+					groups = append(groups, d.Children)
+					return true
+				})
+			default:
+				groups = [][]*Node{node.Children}
+			}
+			for _, group := range groups {
+				for _, match := range step.filter(group) {
+					if !seen[match] {
+						seen[match] = true
+						next = append(next, match)
+					}
+				}
+			}
+		}
+		if step.descendant {
+			next = documentOrder(n, seen)
+		}
+		context = next
+	}
+	return context
+}
+
+// Values returns the text of the elements matching this query relative to the given node.
+func (q *Query) Values(n *Node) []string {
+	var values []string
+	for _, node := range q.Nodes(n) {
+		if !node.Aggregate {
+			values = append(values, node.Text)
+		}
+	}
+	return values
+}
+
+// documentOrder returns the given set of nodes under root in document order.
+func documentOrder(root *Node, set map[*Node]bool) []*Node {
+	var ordered []*Node
+	root.Walk(func(d *Node) bool {
+		if set[d] {
+			ordered = append(ordered, d)
+		}
+		return true
+	})
+	return ordered
+}
+
+// filter returns the given nodes matching the name and predicates of this step.
+func (s queryStep) filter(nodes []*Node) []*Node {
+	var matches []*Node
+	for _, n := range nodes {
+		if s.name == "*" || n.Name == s.name {
+			matches = append(matches, n)
+		}
+	}
+	for _, p := range s.predicates {
+		if p.index > 0 {
+			if p.index > len(matches) {
+				return nil
+			}
+			matches = matches[p.index-1 : p.index]
+			continue
+		}
+		var kept []*Node
+		for _, n := range matches {
+			if p.matches(n) {
+				kept = append(kept, n)
+			}
+		}
+		matches = kept
+	}
+	return matches
+}
+
+// matches returns true if the given node satisfies this predicate.
+func (p queryPredicate) matches(n *Node) bool {
+	if p.name == "." {
+		return p.compare(n.Text, !n.Aggregate)
+	}
+	for _, child := range n.ChildrenNamed(p.name) {
+		if p.op == "" || p.compare(child.Text, !child.Aggregate) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare returns true if the given text of an element satisfies this predicate.
+func (p queryPredicate) compare(text string, isElement bool) bool {
+	switch p.op {
+	case "=":
+		return isElement && text == p.value
+	case "!=":
+		return isElement && text != p.value
+	}
+	return true
+}
+
+// Query returns the nodes matching the given path relative to this node, see Query.
+func (n *Node) Query(path string) ([]*Node, error) {
+	q, err := CompileQuery(path)
+	if err != nil {
+		return nil, err
+	}
+	return q.Nodes(n), nil
+}
+
+// QueryValues returns the text of the elements matching the given path relative to this
+// node, see Query.
+func (n *Node) QueryValues(path string) ([]string, error) {
+	q, err := CompileQuery(path)
+	if err != nil {
+		return nil, err
+	}
+	return q.Values(n), nil
+}
@@ -0,0 +1,68 @@
+package goofx_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("Query", func() {
+		data := "<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>Test Bank<FID>123</FI></SONRS></SIGNONMSGSRSV1>" +
+			"<BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>" +
+			"<STMTTRN><TRNTYPE>FEE<TRNAMT>-1.00<NAME>Fee</STMTTRN>" +
+			"<STMTTRN><TRNTYPE>DEBIT<TRNAMT>-2.00<NAME>Shop</STMTTRN>" +
+			"<STMTTRN><TRNTYPE>FEE<TRNAMT>-3.00<NAME>Shop fee</STMTTRN>" +
+			"</BANKTRANLIST><LEDGERBAL><BALAMT>10.00</LEDGERBAL></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+		var root *goofx.Node
+		BeforeEach(func() {
+			var err error
+			root, err = goofx.NewTree(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+		})
+
+		DescribeTable("Values()", func(path string, expected []string) {
+			Expect(goofx.MustCompileQuery(path).Values(root)).To(Equal(expected))
+		},
+			Entry("path", "SIGNONMSGSRSV1/SONRS/FI/ORG", []string{"Test Bank"}),
+			Entry("absolute path", "/OFX/SIGNONMSGSRSV1/SONRS/FI/FID", []string{"123"}),
+			Entry("lower case", "signonmsgsrsv1/sonrs/fi/fid", []string{"123"}),
+			Entry("equality predicate", "BANKMSGSRSV1/STMTTRNRS/STMTRS/BANKTRANLIST/STMTTRN[TRNTYPE=FEE]/TRNAMT",
+				[]string{"-1.00", "-3.00"}),
+			Entry("inequality predicate", "//STMTTRN[TRNTYPE!=FEE]/NAME", []string{"Shop"}),
+			Entry("quoted value", "//STMTTRN[NAME='Shop fee']/TRNAMT", []string{"-3.00"}),
+			Entry("self predicate", "//STMTTRN/NAME[.=\"Fee\"]", []string{"Fee"}),
+			Entry("index predicate", "//STMTTRN[2]/NAME", []string{"Shop"}),
+			Entry("index predicate for each parent", "//TRNAMT[1]", []string{"-1.00", "-2.00", "-3.00"}),
+			Entry("chained predicates", "//STMTTRN[TRNTYPE=FEE][2]/NAME", []string{"Shop fee"}),
+			Entry("existence predicate", "//*[BALAMT]/BALAMT", []string{"10.00"}),
+			Entry("wildcard", "BANKMSGSRSV1/*/*/LEDGERBAL/BALAMT", []string{"10.00"}),
+			Entry("descendants", "BANKMSGSRSV1//TRNAMT", []string{"-1.00", "-2.00", "-3.00"}),
+			Entry("no match", "//STMTTRN[TRNTYPE=CREDIT]/TRNAMT", []string(nil)),
+			Entry("aggregates have no value", "//STMTTRN", []string(nil)),
+		)
+		It("should return the matching nodes", func() {
+			nodes, err := root.Query("//STMTTRN[TRNTYPE=FEE]")
+			Expect(err).To(BeNil())
+			Expect(nodes).To(HaveLen(2))
+			Expect(nodes[1].Child("NAME").Text).To(Equal("Shop fee"))
+		})
+		DescribeTable("CompileQuery() errors", func(path string) {
+			_, err := goofx.CompileQuery(path)
+			Expect(err).NotTo(BeNil())
+			_, err = root.QueryValues(path)
+			Expect(err).NotTo(BeNil())
+		},
+			Entry("empty", ""),
+			Entry("trailing slash", "OFX/"),
+			Entry("triple slash", "A///B"),
+			Entry("unclosed predicate", "A[B=1"),
+			Entry("invalid index", "A[0]"),
+			Entry("missing predicate name", "A[=1]"),
+		)
+	})
+})
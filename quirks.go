@@ -0,0 +1,268 @@
+package goofx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// tzPattern matches the timezone suffix of an OFX date e.g. [-5:EST].
+var tzPattern = regexp.MustCompile(`\[[^\]]*\]$`)
+
+// Normalizer adjusts a parsed Document, e.g. to correct institution specific conventions.
+type Normalizer func(*Document) error
+
+// Configurable is implemented by cleaners that can be copied with additional CleanerOptions.
+type Configurable interface {
+	// WithOptions returns a new cleaner with the settings of this one and the given options.
+	WithOptions(...CleanerOption) Cleaner
+}
+
+// QuirkProfile describes how a financial institution deviates from the OFX spec, and the
+// fixes to apply to its files. A profile applies to files whose sign-on block matches all of
+// its non-empty Organization, OrganizationID and IntuitBankID fields.
+type QuirkProfile struct {
+	Name           string
+	Organization   string // FI>ORG, matched case-insensitively.
+	OrganizationID string // FI>FID.
+	IntuitBankID   string // INTU.BID.
+	Preprocessors  []Preprocessor
+	CleanerOptions []CleanerOption
+	Normalizers    []Normalizer
+}
+
+// matches returns the number of identifying fields of this profile matching the given
+// institution, or 0 if any of them doesn't match.
+func (p *QuirkProfile) matches(org, fid, bid string) int {
+	count := 0
+	for _, field := range [][2]string{{p.Organization, org}, {p.OrganizationID, fid}, {p.IntuitBankID, bid}} {
+		if field[0] == "" {
+			continue
+		}
+		if !strings.EqualFold(field[0], field[1]) {
+			return 0
+		}
+		count++
+	}
+	return count
+}
+
+// QuirkRegistry holds quirk profiles keyed by the institution they apply to.
+type QuirkRegistry struct {
+	mu       sync.RWMutex
+	profiles []*QuirkProfile
+}
+
+// NewQuirkRegistry returns an empty registry.
+func NewQuirkRegistry() *QuirkRegistry {
+	return &QuirkRegistry{}
+}
+
+// Register adds the given profile to the registry.
+func (r *QuirkRegistry) Register(profile QuirkProfile) error {
+	if profile.Organization == "" && profile.OrganizationID == "" && profile.IntuitBankID == "" {
+		return fmt.Errorf("error - quirk profile %s does not identify an institution", profile.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.profiles = append(r.profiles, &profile)
+	return nil
+}
+
+// Lookup returns the profile for the institution with the given FI>ORG, FI>FID and INTU.BID.
+// If several profiles match, the one matching the most fields wins.
+func (r *QuirkRegistry) Lookup(org, fid, bid string) (*QuirkProfile, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var (
+		best      *QuirkProfile
+		bestCount int
+	)
+	for _, p := range r.profiles {
+		if count := p.matches(org, fid, bid); count > bestCount {
+			best, bestCount = p, count
+		}
+	}
+	return best, best != nil
+}
+
+// lookupData returns the profile for the institution identified by the sign-on block in the
+// given raw data.
+func (r *QuirkRegistry) lookupData(data []byte) (*QuirkProfile, bool) {
+	return r.Lookup(signOnInstitution(data))
+}
+
+// signOnInstitution returns the FI>ORG, FI>FID and INTU.BID of the SONRS aggregate in the
+// given raw data. Tags are matched like the cleaner does, in any case and with any namespace
+// prefix, and end tags are optional.
+func signOnInstitution(data []byte) (org, fid, bid string) {
+	start := findOFXStart(data)
+	if start == -1 {
+		return "", "", ""
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(escapeStrayMarkup(data[start:], SchemaForVersion(ParseHeader(data).Version))))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	var (
+		inSignOn, inFI bool
+		element        string // Last start tag, cleared once its data is read.
+	)
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return org, fid, bid
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element = NormalizeTag(t.Name.Local)
+			switch element {
+			case "SONRS":
+				inSignOn = true
+			case "FI":
+				inFI = inSignOn
+			}
+		case xml.EndElement:
+			switch NormalizeTag(t.Name.Local) {
+			case "SONRS":
+				return org, fid, bid
+			case "FI":
+				inFI = false
+			}
+			element = ""
+		case xml.CharData:
+			value := strings.TrimSpace(string(t))
+			switch {
+			case value == "" || !inSignOn:
+			case inFI && element == "ORG" && org == "":
+				org = value
+			case inFI && element == "FID" && fid == "":
+				fid = value
+			case element == "INTU.BID" && bid == "":
+				bid = value
+			}
+			element = ""
+		}
+	}
+}
+
+// WithQuirks applies the profile from the given registry matching the institution that
+// produced the file, once it is identified by the sign-on block.
+func WithQuirks(registry *QuirkRegistry) ParseOption {
+	return func(c *parseConfig) {
+		c.quirks = registry
+	}
+}
+
+// InvertAmounts returns a normalizer that inverts the sign of transaction amounts, for
+// institutions using the wrong sign convention e.g. on credit card statements.
+func InvertAmounts() Normalizer {
+	return func(d *Document) error {
+		for _, t := range d.transactions() {
+			t.Amount = t.Amount.Neg()
+		}
+		for i := range d.LRMS {
+			txns := d.LRMS[i].TRS.RS.Transactions
+			for j := range txns {
+				txns[j].Principal = txns[j].Principal.Neg()
+				txns[j].Interest = txns[j].Interest.Neg()
+			}
+		}
+		return nil
+	}
+}
+
+// MemoAsName returns a normalizer that moves the memo of transactions without a name to
+// their name, for institutions placing payee text in MEMO.
+func MemoAsName() Normalizer {
+	return func(d *Document) error {
+		for _, t := range d.transactions() {
+			if t.Name == "" && t.Memo != "" {
+				t.Name, t.Memo = t.Memo, ""
+			}
+		}
+		return nil
+	}
+}
+
+// DefaultTimezone returns a normalizer that sets the given timezone on dates without one, for
+// institutions using local time without an offset. The offset is in hours e.g. -5 for EST.
+func DefaultTimezone(offset float64, name string) Normalizer {
+	suffix := fmt.Sprintf("[%g:%s]", offset, name)
+	return func(d *Document) error {
+		for _, date := range d.dates() {
+			if *date != "" && !tzPattern.MatchString(*date) {
+				*date += suffix
+			}
+		}
+		return nil
+	}
+}
+
+// transactions returns pointers to the transactions of all statements in this document.
+func (d *Document) transactions() []*Transaction {
+	var txns []*Transaction
+	add := func(t []Transaction) {
+		for i := range t {
+			txns = append(txns, &t[i])
+		}
+	}
+	for i := range d.BRMS {
+		add(d.BRMS[i].TRS.RS.Transactions)
+	}
+	for i := range d.CCRMS {
+		add(d.CCRMS[i].TRS.RS.Transactions)
+	}
+	for i := range d.IRMS {
+		t := d.IRMS[i].TRS.RS.Transactions.Transactions
+		for j := range t {
+			txns = append(txns, &t[j].Transaction)
+		}
+	}
+	for i := range d.LRMS {
+		t := d.LRMS[i].TRS.RS.Transactions
+		for j := range t {
+			txns = append(txns, &t[j].Transaction)
+		}
+	}
+	return txns
+}
+
+// dates returns pointers to all date values in this document.
+func (d *Document) dates() []*string {
+	dates := []*string{&d.Response.Date}
+	balances := func(l BalanceList) {
+		for i := range l {
+			dates = append(dates, &l[i].Date)
+		}
+	}
+	for i := range d.BRMS {
+		rs := &d.BRMS[i].TRS.RS
+		dates = append(dates, &rs.StartDate, &rs.EndDate, &rs.LedgerBalance.Date, &rs.AvailableBalance.Date)
+		balances(rs.Balances)
+	}
+	for i := range d.CCRMS {
+		rs := &d.CCRMS[i].TRS.RS
+		dates = append(dates, &rs.StartDate, &rs.EndDate, &rs.LedgerBalance.Date, &rs.AvailableBalance.Date)
+		balances(rs.Balances)
+	}
+	for i := range d.IRMS {
+		rs := &d.IRMS[i].TRS.RS
+		dates = append(dates, &rs.Date, &rs.Transactions.StartDate, &rs.Transactions.EndDate)
+		for j := range rs.Positions.Positions {
+			dates = append(dates, &rs.Positions.Positions[j].PriceDate)
+		}
+		balances(rs.Balance.Balances)
+	}
+	for i := range d.LRMS {
+		rs := &d.LRMS[i].TRS.RS
+		dates = append(dates, &rs.StartDate, &rs.EndDate)
+		balances(rs.Balances)
+	}
+	for _, t := range d.transactions() {
+		dates = append(dates, &t.Posted, &t.Date, &t.Available)
+	}
+	return dates
+}
@@ -0,0 +1,142 @@
+package goofx_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("QuirkRegistry", func() {
+		var registry *goofx.QuirkRegistry
+		BeforeEach(func() {
+			registry = goofx.NewQuirkRegistry()
+			Expect(registry.Register(goofx.QuirkProfile{Name: "org", Organization: "Test Bank"})).To(Succeed())
+			Expect(registry.Register(goofx.QuirkProfile{Name: "org-fid", Organization: "Test Bank", OrganizationID: "123"})).To(Succeed())
+			Expect(registry.Register(goofx.QuirkProfile{Name: "bid", IntuitBankID: "9999"})).To(Succeed())
+		})
+
+		DescribeTable("Lookup()",
+			func(org, fid, bid, expected string) {
+				p, found := registry.Lookup(org, fid, bid)
+				if expected == "" {
+					Expect(found).To(BeFalse())
+					return
+				}
+				Expect(found).To(BeTrue())
+				Expect(p.Name).To(Equal(expected))
+			},
+			Entry("no match", "Other Bank", "123", "", ""),
+			Entry("org match", "test bank", "456", "", "org"),
+			Entry("most specific match", "Test Bank", "123", "", "org-fid"),
+			Entry("intuit bank id match", "", "", "9999", "bid"),
+		)
+
+		It("should reject profiles not identifying an institution", func() {
+			Expect(registry.Register(goofx.QuirkProfile{Name: "none"})).NotTo(Succeed())
+		})
+	})
+	Describe("Configurable", func() {
+		It("should apply options to a copy of the cleaner", func() {
+			data := []byte("<OFX><STATUS><CODE>0</STATUS>footer</OFX>")
+			cleaner := goofx.NewCleaner()
+			clone := cleaner.(goofx.Configurable).WithOptions(goofx.WithOrphanPolicy(goofx.OrphanDrop))
+			Expect(clone.Init(data)).To(Succeed())
+			_, err := clone.CleanupXML()
+			Expect(err).To(BeNil())
+			Expect(cleaner.Init(data)).To(Succeed())
+			_, err = cleaner.CleanupXML()
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Describe("NewDocumentFromXML() with quirks", func() {
+		data := "<OFX><SIGNONMSGSRSV1><SONRS><DTSERVER>20190131<FI><ORG>Test Bank<FID>123</FI></SONRS></SIGNONMSGSRSV1>" +
+			"<BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST><DTSTART>20190101<DTEND>20190131[-8:PST]" +
+			"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20190119<TRNAMT>20.96<FITID>1<MEMO1>Sample Expense</STMTTRN>" +
+			"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+		var registry *goofx.QuirkRegistry
+		BeforeEach(func() {
+			registry = goofx.NewQuirkRegistry()
+			Expect(registry.Register(goofx.QuirkProfile{
+				Name:         "test-bank",
+				Organization: "Test Bank",
+				Preprocessors: []goofx.Preprocessor{goofx.NewPreprocessor("memo1", func(data []byte) ([]byte, error) {
+					return bytes.Replace(data, []byte("<MEMO1>"), []byte("<MEMO>"), -1), nil
+				})},
+				CleanerOptions: []goofx.CleanerOption{goofx.WithOrphanPolicy(goofx.OrphanDrop)},
+				Normalizers: []goofx.Normalizer{
+					goofx.InvertAmounts(), goofx.MemoAsName(), goofx.DefaultTimezone(-5, "EST"),
+				},
+			})).To(Succeed())
+		})
+
+		Context("when the institution has a quirk profile", func() {
+			It("should apply its fixes", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner(), goofx.WithQuirks(registry))
+				Expect(err).To(BeNil())
+				Expect(d.Quirks).To(Equal("test-bank"))
+				Expect(d.Response.Date).To(Equal("20190131[-5:EST]"))
+				rs := d.BRMS[0].TRS.RS
+				Expect(rs.StartDate).To(Equal("20190101[-5:EST]"))
+				Expect(rs.EndDate).To(Equal("20190131[-8:PST]"))
+				Expect(rs.Transactions).To(HaveLen(1))
+				Expect(rs.Transactions[0].Amount.String()).To(Equal("-20.96"))
+				Expect(rs.Transactions[0].Name).To(Equal("Sample Expense"))
+				Expect(rs.Transactions[0].Memo).To(BeEmpty())
+				Expect(rs.Transactions[0].Posted).To(Equal("20190119[-5:EST]"))
+			})
+			It("should apply its cleaner options", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(strings.Replace(data, "</SONRS>", "</SONRS>footer", 1)),
+					goofx.NewCleaner(), goofx.WithQuirks(registry))
+				Expect(err).To(BeNil())
+				Expect(d.Quirks).To(Equal("test-bank"))
+			})
+		})
+		Context("when the sign-on tags are lowercase or namespace prefixed", func() {
+			It("should identify the institution", func() {
+				prefixed := strings.NewReplacer("<ORG>", "<ofx:org>", "<FID>", "<fid>", "</FI>", "</ofx:FI>").Replace(data)
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(prefixed), goofx.NewCleaner(), goofx.WithQuirks(registry))
+				Expect(err).To(BeNil())
+				Expect(d.Quirks).To(Equal("test-bank"))
+			})
+		})
+		Context("when the file holds a credit card statement", func() {
+			It("should normalize its transactions", func() {
+				r := strings.NewReader("<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>Test Bank</FI></SONRS></SIGNONMSGSRSV1>" +
+					"<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><BANKTRANLIST><DTSTART>20190101<DTEND>20190131" +
+					"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20190119<TRNAMT>20.96<FITID>1<MEMO>Shop</STMTTRN>" +
+					"</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>")
+				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner(), goofx.WithQuirks(registry))
+				Expect(err).To(BeNil())
+				rs := d.CCRMS[0].TRS.RS
+				Expect(rs.StartDate).To(Equal("20190101[-5:EST]"))
+				Expect(rs.Transactions[0].Amount.String()).To(Equal("-20.96"))
+				Expect(rs.Transactions[0].Name).To(Equal("Shop"))
+				Expect(rs.Transactions[0].Posted).To(Equal("20190119[-5:EST]"))
+			})
+		})
+		Context("when an ORG appears outside the sign-on block", func() {
+			It("should not match it", func() {
+				r := strings.NewReader(strings.NewReplacer("<ORG>Test Bank", "",
+					"<STMTTRNRS>", "<STMTTRNRS><ORG>Test Bank").Replace(data))
+				d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner(), goofx.WithQuirks(registry))
+				Expect(err).To(BeNil())
+				Expect(d.Quirks).To(BeEmpty())
+			})
+		})
+		Context("when the institution has no quirk profile", func() {
+			It("should parse the file as is", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(strings.Replace(data, "Test Bank", "Other Bank", 1)),
+					goofx.NewCleaner(), goofx.WithQuirks(registry))
+				Expect(err).To(BeNil())
+				Expect(d.Quirks).To(BeEmpty())
+				Expect(d.BRMS[0].TRS.RS.Transactions[0].Amount.String()).To(Equal("20.96"))
+			})
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package goofx
+
+import (
+	"context"
+	"io"
+	"regexp"
+)
+
+const (
+	// scanChunkSize is the number of bytes read from the source at a time.
+	scanChunkSize = 32 * 1024
+	// scanOverlap is the number of already scanned bytes that are scanned again after
+	// reading more data, so tags split across chunks are found.
+	scanOverlap = 64
+)
+
+// ofxEndPattern matches the OFX end tag in any case and with any namespace prefix.
+var ofxEndPattern = regexp.MustCompile(`(?i)</(?:[\w.-]+:)?OFX\s*>`)
+
+// DocumentScanner reads consecutive OFX documents, each with its own header, from a
+// single reader e.g. aggregator exports or concatenated downloads. UTF-16 input is
+// converted to UTF-8.
+//
+// Its usage is similar to bufio.Scanner.
+//
+//	scanner := goofx.NewDocumentScanner(reader, func() goofx.Cleaner { return goofx.NewCleaner() })
+//	for scanner.Scan() {
+//		document := scanner.Document()
+//	}
+//	if err := scanner.Err(); err != nil {
+//		...
+//	}
+type DocumentScanner struct {
+	reader     io.Reader
+	newCleaner func() Cleaner
+	config     *parseConfig
+	document   *Document
+	err        error
+	done       bool
+
+	buf     []byte // Data read from the reader but not returned as a document yet.
+	start   int    // Index of the OFX start tag of the next document in buf, -1 if not found.
+	scanned int    // Number of bytes in buf already scanned for document boundaries.
+	eof     bool   // Set once the reader is exhausted.
+}
+
+// NewDocumentScanner returns a scanner reading OFX documents from the given reader. Each
+// document is cleaned by a new cleaner returned by newCleaner.
+// Limits.MaxBytes of the cleaner applies to each document after conversion to UTF-8.
+func NewDocumentScanner(reader io.Reader, newCleaner func() Cleaner, opts ...ParseOption) *DocumentScanner {
+	return &DocumentScanner{
+		reader:     newUTF8Reader(reader),
+		newCleaner: newCleaner,
+		config:     newParseConfig(opts),
+		start:      -1,
+	}
+}
+
+// Scan advances the scanner to the next document, which is then available through Document.
+// It returns false when there are no more documents or an error occurred.
+func (s *DocumentScanner) Scan() bool {
+	return s.ScanContext(context.Background())
+}
+
+// ScanContext is like Scan but stops and records ctx.Err() once the given context is done.
+func (s *DocumentScanner) ScanContext(ctx context.Context) bool {
+	s.document = nil
+	if s.done {
+		return false
+	}
+	cleaner := s.newCleaner()
+	// Documents missing the OFX end tag end at the header of the next one.
+	if c, ok := cleaner.(Configurable); ok {
+		cleaner = c.WithOptions(withCloseAtEnd())
+	}
+	var limits Limits
+	if l, ok := cleaner.(Limiter); ok {
+		limits = l.Limits()
+	}
+	data, err := s.next(ctx, limits)
+	if err == nil {
+		s.document, err = newDocument(ctx, data, cleaner, limits, s.config)
+	}
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		s.done = true
+		return false
+	}
+	return true
+}
+
+// Document returns the document read by the last call to Scan.
+func (s *DocumentScanner) Document() *Document {
+	return s.document
+}
+
+// Err returns the first error encountered by the scanner, if any.
+func (s *DocumentScanner) Err() error {
+	return s.err
+}
+
+// next returns the data of the next document, reading more from the reader as needed.
+func (s *DocumentScanner) next(ctx context.Context, limits Limits) ([]byte, error) {
+	for {
+		if data, found := s.split(); found {
+			return data, nil
+		}
+		if s.eof {
+			data := s.take(len(s.buf))
+			if findOFXStart(data) == -1 {
+				return nil, io.EOF
+			}
+			return data, nil
+		}
+		if limits.MaxBytes > 0 && int64(len(s.buf)) > limits.MaxBytes {
+			return nil, ErrInputTooLarge
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := s.fill(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fill reads the next chunk of data from the reader into buf.
+func (s *DocumentScanner) fill() error {
+	chunk := make([]byte, scanChunkSize)
+	n, err := s.reader.Read(chunk)
+	s.buf = append(s.buf, chunk[:n]...)
+	if err == io.EOF {
+		s.eof = true
+		return nil
+	}
+	return err
+}
+
+// split returns the data of the first document in buf if its end has been read. A document
+// ends after its OFX end tag or, if that is missing, at the header of the next document.
+func (s *DocumentScanner) split() ([]byte, bool) {
+	from := s.scanned - scanOverlap
+	if from < 0 {
+		from = 0
+	}
+	s.scanned = len(s.buf)
+	if s.start == -1 {
+		i := findOFXStart(s.buf[from:])
+		if i == -1 {
+			return nil, false
+		}
+		s.start = from + i
+	}
+	if from <= s.start {
+		from = s.start + 1
+	}
+
+	end, next := -1, -1
+	if loc := ofxEndPattern.FindIndex(s.buf[from:]); loc != nil {
+		end = from + loc[1]
+	}
+	if i := findOFXStart(s.buf[from:]); i != -1 {
+		next = from + i
+	}
+	switch {
+	case end != -1 && (next == -1 || end < next):
+		return s.take(end), true
+	case next != -1:
+		if h := lastHeaderStart(s.buf[s.start:next]); h > 0 {
+			return s.take(s.start + h), true
+		}
+		return s.take(next), true
+	}
+	return nil, false
+}
+
+// take removes and returns the first n bytes of buf.
+func (s *DocumentScanner) take(n int) []byte {
+	data := make([]byte, n)
+	copy(data, s.buf[:n])
+	s.buf = s.buf[n:]
+	s.start = -1
+	s.scanned = 0
+	return data
+}
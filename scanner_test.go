@@ -0,0 +1,116 @@
+package goofx_test
+
+import (
+	"errors"
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+const sgmlDocument = "OFXHEADER:100\nDATA:OFXSGML\nVERSION:%s\n\n" +
+	"<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>%s</FI></SONRS></SIGNONMSGSRSV1>" +
+	"<BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST><STMTTRN><FITID>1</STMTTRN></BANKTRANLIST>" +
+	"</STMTRS></STMTTRNRS></BANKMSGSRSV1>"
+
+var _ = Describe("goofx", func() {
+	Describe("DocumentScanner", func() {
+		var newCleaner = func() goofx.Cleaner { return goofx.NewCleaner() }
+		var scanAll = func(scanner *goofx.DocumentScanner) []*goofx.Document {
+			documents := make([]*goofx.Document, 0)
+			for scanner.Scan() {
+				documents = append(documents, scanner.Document())
+			}
+			return documents
+		}
+
+		Context("when given concatenated OFX 1.x documents", func() {
+			It("should return each document with its own header", func() {
+				data := strings.Replace(strings.Replace(sgmlDocument, "%s", "102", 1), "%s", "Bank A", 1) + "</OFX>\n" +
+					strings.Replace(strings.Replace(sgmlDocument, "%s", "103", 1), "%s", "Bank B", 1) + "</OFX>\n"
+				scanner := goofx.NewDocumentScanner(strings.NewReader(data), newCleaner)
+				documents := scanAll(scanner)
+				Expect(scanner.Err()).To(BeNil())
+				Expect(documents).To(HaveLen(2))
+				Expect(documents[0].Header.Version).To(Equal("102"))
+				Expect(documents[0].Response.Organization).To(Equal("Bank A"))
+				Expect(documents[0].TransactionCount).To(Equal(1))
+				Expect(documents[1].Header.Version).To(Equal("103"))
+				Expect(documents[1].Response.Organization).To(Equal("Bank B"))
+				Expect(documents[1].TransactionCount).To(Equal(1))
+			})
+			It("should split documents missing the OFX end tag at the next header", func() {
+				data := strings.Replace(strings.Replace(sgmlDocument, "%s", "102", 1), "%s", "Bank A", 1) + "\n" +
+					strings.Replace(strings.Replace(sgmlDocument, "%s", "103", 1), "%s", "Bank B", 1)
+				scanner := goofx.NewDocumentScanner(iotest.OneByteReader(strings.NewReader(data)), newCleaner)
+				documents := scanAll(scanner)
+				Expect(scanner.Err()).To(BeNil())
+				Expect(documents).To(HaveLen(2))
+				Expect(documents[0].Header.Version).To(Equal("102"))
+				Expect(documents[0].Response.Organization).To(Equal("Bank A"))
+				Expect(documents[1].Header.Version).To(Equal("103"))
+				Expect(documents[1].Response.Organization).To(Equal("Bank B"))
+			})
+			It("should not change the cleaner returned by newCleaner", func() {
+				cleaner := goofx.NewCleaner()
+				data := strings.Replace(strings.Replace(sgmlDocument, "%s", "102", 1), "%s", "Bank A", 1)
+				scanner := goofx.NewDocumentScanner(strings.NewReader(data), func() goofx.Cleaner { return cleaner })
+				Expect(scanAll(scanner)).To(HaveLen(1))
+				Expect(scanner.Err()).To(BeNil())
+				_, err := goofx.NewDocumentFromXML(strings.NewReader(data), cleaner)
+				Expect(err).To(MatchError("error - aggregate OFX not closed at end of data"))
+			})
+		})
+		Context("when given concatenated OFX 2.x documents", func() {
+			It("should return each document with its own header", func() {
+				document := `<?xml version="1.0"?><?OFX OFXHEADER="200" VERSION="%s"?>` +
+					`<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>Bank</ORG></FI></SONRS></SIGNONMSGSRSV1></OFX>`
+				data := strings.Replace(document, "%s", "211", 1) + "\n" + strings.Replace(document, "%s", "220", 1)
+				scanner := goofx.NewDocumentScanner(iotest.HalfReader(strings.NewReader(data)), newCleaner)
+				documents := scanAll(scanner)
+				Expect(scanner.Err()).To(BeNil())
+				Expect(documents).To(HaveLen(2))
+				Expect(documents[0].Header.Version).To(Equal("211"))
+				Expect(documents[0].Path).To(Equal(goofx.ParsePathDirect))
+				Expect(documents[1].Header.Version).To(Equal("220"))
+				Expect(documents[1].Response.Organization).To(Equal("Bank"))
+			})
+		})
+		Context("when given no OFX documents", func() {
+			It("should not return any documents", func() {
+				scanner := goofx.NewDocumentScanner(strings.NewReader("not an OFX file"), newCleaner)
+				Expect(scanAll(scanner)).To(BeEmpty())
+				Expect(scanner.Err()).To(BeNil())
+			})
+		})
+		Context("when a document can not be parsed", func() {
+			It("should stop and return the error", func() {
+				data := "<OFX></OFX><OFX>foo<STMTTRN></OFX>"
+				scanner := goofx.NewDocumentScanner(strings.NewReader(data), newCleaner)
+				Expect(scanAll(scanner)).To(HaveLen(1))
+				Expect(scanner.Err()).To(HaveOccurred())
+				Expect(scanner.Scan()).To(BeFalse())
+			})
+		})
+		Context("when the reader fails", func() {
+			It("should return the error", func() {
+				scanner := goofx.NewDocumentScanner(FakeReader{err: errors.New("fake reader test error")}, newCleaner)
+				Expect(scanAll(scanner)).To(BeEmpty())
+				Expect(scanner.Err()).To(MatchError("fake reader test error"))
+			})
+		})
+		Context("when a document exceeds the size limit", func() {
+			It("should return an error", func() {
+				limited := func() goofx.Cleaner {
+					return goofx.NewCleaner(goofx.WithLimits(goofx.Limits{MaxBytes: 16}))
+				}
+				scanner := goofx.NewDocumentScanner(strings.NewReader("<OFX>"+strings.Repeat(" ", 64)), limited)
+				Expect(scanAll(scanner)).To(BeEmpty())
+				Expect(scanner.Err()).To(MatchError(goofx.ErrInputTooLarge))
+			})
+		})
+	})
+})
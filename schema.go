@@ -0,0 +1,188 @@
+package goofx
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/rockstardevs/goofx/spec"
+)
+
+// Spec levels, ordered by the features they define. SGML and XML versions defining the same
+// features share a level, e.g. OFX 1.6 and 2.1.
+const (
+	level102 = iota + 1
+	level103
+	level151
+	level200
+	level201
+	level202
+	level203
+	level210
+	level211
+	level220
+	latestLevel = level220
+)
+
+// versionLevels maps the VERSION header values to spec levels.
+var versionLevels = map[string]int{
+	"102": level102,
+	"103": level103,
+	"151": level151,
+	"200": level200,
+	"201": level201,
+	"202": level202,
+	"203": level203,
+	"160": level210, "210": level210,
+	"211": level211,
+	"220": level220,
+}
+
+// levelNames holds the first spec version of each level.
+var levelNames = map[int]string{
+	level102: "1.0.2",
+	level103: "1.0.3",
+	level151: "1.5.1",
+	level200: "2.0",
+	level201: "2.0.1",
+	level202: "2.0.2",
+	level203: "2.0.3",
+	level210: "2.1",
+	level211: "2.1.1",
+	level220: "2.2",
+}
+
+// versionedTags lists the tags introduced after OFX 1.0.2 by the level introducing them.
+// Tags from GetAggregates and GetElements are defined by all versions. Tags generated from the
+// XSD schemas into package spec are defined from the version of those schemas on.
+var versionedTags = []struct {
+	level      int
+	aggregates []string
+	elements   []string
+}{
+	{
+		level: level151,
+		aggregates: []string{
+			"STMTENDTRNRQ", "STMTENDRQ", "STMTENDTRNRS", "STMTENDRS", "CLOSING",
+			"LOANMSGSRSV1", "LOANSTMTTRNRS", "LOANSTMTRS", "LOANACCTFROM", "LOANTRANLIST",
+			"LOANSTMTTRN", "LOANTRNAMT",
+		},
+		elements: []string{
+			"DTOPEN", "DTCLOSE", "DTNEXT", "BALOPEN", "BALCLOSE", "BALMIN", "DEPANDCREDIT",
+			"CHKANDDEBIT", "TOTALFEES", "TOTALINT", "DTPOSTSTART", "DTPOSTEND",
+			"LOANACCTID", "LOANACCTTYPE", "PRINAMT", "INTAMT",
+		},
+	},
+	{
+		level:      level210,
+		aggregates: []string{"IMAGEDATA"},
+		elements:   []string{"IMAGETYPE", "IMAGEREF", "IMAGEREFTYPE", "IMAGEDELAY", "DTIMAGEAVAIL", "IMAGETTL", "CHECKSUP", "INCIMAGES"},
+	},
+	{
+		level: level211,
+		aggregates: []string{
+			"MFACHALLENGETRNRQ", "MFACHALLENGERQ", "MFACHALLENGETRNRS", "MFACHALLENGERS",
+			"MFACHALLENGE", "MFACHALLENGEA",
+		},
+		elements: []string{"MFAPHRASEID", "MFAPHRASELABEL", "MFAPHRASEA"},
+	},
+	{
+		level:    level220,
+		elements: []string{"ACCESSTOKEN"},
+	},
+}
+
+// schemaTag is a tag known to some version of the spec.
+type schemaTag struct {
+	aggregate bool
+	level     int // Level introducing the tag.
+}
+
+var schemaTags map[string]schemaTag
+var initSchemaTags sync.Once
+
+// getSchemaTags returns the singleton map of all tags known to any version of the spec.
+func getSchemaTags() map[string]schemaTag {
+	initSchemaTags.Do(func() {
+		schemaTags = make(map[string]schemaTag)
+		for a := range GetAggregates() {
+			schemaTags[a] = schemaTag{aggregate: true, level: level102}
+		}
+		for e := range GetElements() {
+			schemaTags[e] = schemaTag{level: level102}
+		}
+		for _, v := range versionedTags {
+			for _, a := range v.aggregates {
+				schemaTags[a] = schemaTag{aggregate: true, level: v.level}
+			}
+			for _, e := range v.elements {
+				schemaTags[e] = schemaTag{level: v.level}
+			}
+		}
+		level, found := versionLevels[spec.Version]
+		if !found {
+			level = latestLevel
+		}
+		for _, a := range spec.Aggregates {
+			if _, known := schemaTags[a]; !known {
+				schemaTags[a] = schemaTag{aggregate: true, level: level}
+			}
+		}
+		for _, e := range spec.Elements {
+			if _, known := schemaTags[e]; !known {
+				schemaTags[e] = schemaTag{level: level}
+			}
+		}
+	})
+	return schemaTags
+}
+
+// Schema holds the tags defined by a version of the OFX spec.
+type Schema struct {
+	Version string // VERSION header value, empty for the latest version.
+	level   int
+}
+
+// SchemaForVersion returns the schema for the given VERSION header value e.g. 102 or 220.
+// Unknown versions get the latest schema.
+func SchemaForVersion(version string) *Schema {
+	version = strings.Replace(strings.TrimSpace(version), ".", "", -1)
+	level, found := versionLevels[version]
+	if !found {
+		return &Schema{level: latestLevel}
+	}
+	return &Schema{Version: version, level: level}
+}
+
+// lookup returns the given tag if it is defined by this schema.
+func (s *Schema) lookup(tag string) (schemaTag, bool) {
+	t, found := getSchemaTags()[NormalizeTag(tag)]
+	return t, found && t.level <= s.level
+}
+
+// IsAggregate returns true if the given tag is an aggregate tag in this schema.
+func (s *Schema) IsAggregate(tag string) bool {
+	t, found := s.lookup(tag)
+	return found && t.aggregate
+}
+
+// IsElement returns true if the given tag is an element tag in this schema.
+func (s *Schema) IsElement(tag string) bool {
+	t, found := s.lookup(tag)
+	return found && !t.aggregate
+}
+
+// IsKnownTag returns true if the given tag is an aggregate or element tag in this schema, or
+// an extension tag.
+func (s *Schema) IsKnownTag(tag string) bool {
+	_, found := s.lookup(tag)
+	return found || strings.Contains(tag, ".")
+}
+
+// Since returns the first spec version defining the given tag, if any version does.
+func Since(tag string) (string, bool) {
+	t, found := getSchemaTags()[NormalizeTag(tag)]
+	if !found {
+		return "", false
+	}
+	return levelNames[t.level], true
+}
@@ -0,0 +1,87 @@
+package goofx_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("Schema", func() {
+		DescribeTable("IsAggregate()", func(version, tag string, expected bool) {
+			Expect(goofx.SchemaForVersion(version).IsAggregate(tag)).To(Equal(expected))
+		},
+			Entry("base aggregate in 1.0.2", "102", "STMTTRN", true),
+			Entry("closing statement in 1.0.2", "102", "STMTENDRS", false),
+			Entry("closing statement in 2.0", "200", "STMTENDRS", true),
+			Entry("image data in 2.0.2", "202", "IMAGEDATA", false),
+			Entry("closing statement in 2.0.3", "203", "STMTENDRS", true),
+			Entry("image data in 2.0.3", "203", "IMAGEDATA", false),
+			Entry("image data in 1.6", "160", "IMAGEDATA", true),
+			Entry("mfa challenge in 2.1", "210", "MFACHALLENGERS", false),
+			Entry("mfa challenge in 2.1.1", "211", "MFACHALLENGERS", true),
+			Entry("unknown version", "", "MFACHALLENGERS", true),
+		)
+		DescribeTable("IsElement()", func(version, tag string, expected bool) {
+			Expect(goofx.SchemaForVersion(version).IsElement(tag)).To(Equal(expected))
+		},
+			Entry("base element in 1.0.2", "102", "TRNAMT", true),
+			Entry("investment element in 1.0.2", "102", "UNITSSTREET", true),
+			Entry("access token in 2.1.1", "211", "ACCESSTOKEN", false),
+			Entry("access token in 2.2", "220", "accesstoken", true),
+			Entry("aggregate", "220", "IMAGEDATA", false),
+		)
+		DescribeTable("Since()", func(tag, expected string, found bool) {
+			since, ok := goofx.Since(tag)
+			Expect(ok).To(Equal(found))
+			Expect(since).To(Equal(expected))
+		},
+			Entry("base tag", "OFX", "1.0.2", true),
+			Entry("versioned tag", "MFAPHRASEID", "2.1.1", true),
+			Entry("unknown tag", "FOO", "", false),
+		)
+	})
+	Describe("NewDocumentFromXML() with a VERSION header", func() {
+		data := "OFXHEADER:100\nDATA:OFXSGML\nVERSION:%s\n\n<OFX><BANKMSGSRSV1>" +
+			"<STMTENDTRNRS><TRNUID>1<STMTENDRS><CLOSING><FITID>1<BALCLOSE>5.00</CLOSING></STMTENDRS></STMTENDTRNRS>" +
+			"<STMTTRNRS><TRNUID>2</STMTTRNRS></BANKMSGSRSV1></OFX>"
+		unsupported := func(d *goofx.Document) []string {
+			var tags []string
+			for _, diagnostic := range d.Diagnostics {
+				if diagnostic.Kind == goofx.DiagnosticUnsupportedTag {
+					tags = append(tags, diagnostic.Tag)
+				}
+			}
+			return tags
+		}
+
+		Context("when the file uses tags defined by its version", func() {
+			It("should infer them as per that version", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(strings.Replace(data, "%s", "151", 1)), goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(unsupported(d)).To(BeEmpty())
+				Expect(d.BRMS).To(HaveLen(1))
+				Expect(d.BRMS[0].TRS.ID).To(Equal("2"))
+			})
+		})
+		Context("when the file uses tags from a later version", func() {
+			It("should report them", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(strings.Replace(data, "%s", "102", 1)), goofx.NewCleaner())
+				Expect(err).To(BeNil())
+				Expect(unsupported(d)).To(Equal([]string{"STMTENDTRNRS", "STMTENDRS", "CLOSING", "BALCLOSE"}))
+			})
+		})
+		Context("when the cleaner version is set", func() {
+			It("should override the VERSION header", func() {
+				d, err := goofx.NewDocumentFromXML(strings.NewReader(strings.Replace(data, "%s", "102", 1)),
+					goofx.NewCleaner(goofx.WithVersion("220")))
+				Expect(err).To(BeNil())
+				Expect(unsupported(d)).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,16 @@
+// Package spec holds the OFX types generated from the XSD schemas of the OFX 2.x spec.
+//
+// The types are generated from a subset of the OFX 2.2 schemas in the xsd directory, see
+// xsd/README.md.
+package spec
+
+//go:generate go run ../internal/ofxgen -xsd xsd -out types.go -package spec -version 220
+
+// Version is the VERSION of the spec the types were generated from, empty if none were.
+var Version string
+
+// Aggregates holds the aggregate tags defined by the schemas.
+var Aggregates []string
+
+// Elements holds the element tags defined by the schemas.
+var Elements []string
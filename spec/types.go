@@ -0,0 +1,595 @@
+// Code generated by ofxgen from bank.xsd, common.xsd, investment.xsd, loan.xsd, signon.xsd. DO NOT EDIT.
+
+package spec
+
+func init() {
+	Version = "220"
+	Aggregates = append(Aggregates, "AVAILBAL", "BAL", "BALLIST", "BANKACCTFROM", "BANKACCTTO", "BANKMSGSRSV1", "BANKTRANLIST", "BUYSTOCK", "CCACCTFROM", "CCACCTTO", "CCSTMTRS", "CCSTMTTRNRS", "CLOSING", "CREDITCARDMSGSRSV1", "CURRENCY", "FI", "INCOME", "INVACCTFROM", "INVBAL", "INVBANKTRAN", "INVBUY", "INVPOS", "INVPOSLIST", "INVSELL", "INVSTMTMSGSRSV1", "INVSTMTRS", "INVSTMTTRNRS", "INVTRAN", "INVTRANLIST", "LEDGERBAL", "LOANACCTFROM", "LOANMSGSRSV1", "LOANSTMTRS", "LOANSTMTTRN", "LOANSTMTTRNRS", "LOANTRANLIST", "LOANTRNAMT", "ORIGCURRENCY", "PAYEE", "POSMF", "POSSTOCK", "SECID", "SELLSTOCK", "SIGNONMSGSRSV1", "SONRS", "STATUS", "STMTENDRS", "STMTENDTRNRS", "STMTRS", "STMTTRN", "STMTTRNRS")
+	Elements = append(Elements, "ACCESSKEY", "ACCESSTOKEN", "ACCTID", "ACCTKEY", "ACCTTYPE", "ADDR1", "ADDR2", "ADDR3", "AVAILCASH", "BALAMT", "BALCLOSE", "BALMIN", "BALOPEN", "BALTYPE", "BANKID", "BRANCHID", "BROKERID", "BUYPOWER", "BUYTYPE", "CHECKNUM", "CHKANDDEBIT", "CITY", "CLTCOOKIE", "CODE", "COMMISSION", "CORRECTACTION", "CORRECTFITID", "COUNTRY", "CURDEF", "CURRATE", "CURSYM", "DEPANDCREDIT", "DESC", "DTACCTUP", "DTASOF", "DTAVAIL", "DTCLOSE", "DTEND", "DTNEXT", "DTOPEN", "DTPOSTED", "DTPOSTEND", "DTPOSTSTART", "DTPRICEASOF", "DTPROFUP", "DTSERVER", "DTSETTLE", "DTSTART", "DTTRADE", "DTUSER", "EXTDNAME", "FEES", "FID", "FITID", "GAIN", "HELDINACCT", "INCOMETYPE", "INTAMT", "INV401KSOURCE", "LANGUAGE", "LOAD", "LOANACCTID", "LOANACCTTYPE", "MARGINBALANCE", "MARKDOWN", "MARKUP", "MEMO", "MESSAGE", "MKTGINFO", "MKTVAL", "NAME", "ORG", "PAYEEID", "PHONE", "POSTALCODE", "POSTYPE", "PRINAMT", "REFNUM", "REINVCG", "REINVDIV", "REVERSALFITID", "SELLTYPE", "SESSCOOKIE", "SEVERITY", "SHORTBALANCE", "SIC", "SRVRTID", "STATE", "SUBACCTFUND", "SUBACCTSEC", "TAXES", "TAXEXEMPT", "TOTAL", "TOTALFEES", "TOTALINT", "TRNAMT", "TRNTYPE", "TRNUID", "TSKEYEXPIRE", "UNIQUEID", "UNIQUEIDTYPE", "UNITPRICE", "UNITS", "UNITSSTREET", "UNITSUSER", "USERKEY", "VALUE", "WITHHOLDING")
+}
+
+// TransactionEnum is the TRNTYPE of a statement transaction.
+type TransactionEnum string
+
+const (
+	TransactionEnumCredit      TransactionEnum = "CREDIT"
+	TransactionEnumDebit       TransactionEnum = "DEBIT"
+	TransactionEnumInt         TransactionEnum = "INT"
+	TransactionEnumDiv         TransactionEnum = "DIV"
+	TransactionEnumFee         TransactionEnum = "FEE"
+	TransactionEnumSrvchg      TransactionEnum = "SRVCHG"
+	TransactionEnumDep         TransactionEnum = "DEP"
+	TransactionEnumAtm         TransactionEnum = "ATM"
+	TransactionEnumPos         TransactionEnum = "POS"
+	TransactionEnumXfer        TransactionEnum = "XFER"
+	TransactionEnumCheck       TransactionEnum = "CHECK"
+	TransactionEnumPayment     TransactionEnum = "PAYMENT"
+	TransactionEnumCash        TransactionEnum = "CASH"
+	TransactionEnumDirectdep   TransactionEnum = "DIRECTDEP"
+	TransactionEnumDirectdebit TransactionEnum = "DIRECTDEBIT"
+	TransactionEnumRepeatpmt   TransactionEnum = "REPEATPMT"
+	TransactionEnumHold        TransactionEnum = "HOLD"
+	TransactionEnumOther       TransactionEnum = "OTHER"
+)
+
+// CorrectiveActionEnum is the CORRECTACTION of a transaction correcting another.
+type CorrectiveActionEnum string
+
+const (
+	CorrectiveActionEnumReplace CorrectiveActionEnum = "REPLACE"
+	CorrectiveActionEnumDelete  CorrectiveActionEnum = "DELETE"
+)
+
+// Inv401kSourceEnum is the source of money in a 401(k) account.
+type Inv401kSourceEnum string
+
+const (
+	Inv401kSourceEnumPretax        Inv401kSourceEnum = "PRETAX"
+	Inv401kSourceEnumAftertax      Inv401kSourceEnum = "AFTERTAX"
+	Inv401kSourceEnumMatch         Inv401kSourceEnum = "MATCH"
+	Inv401kSourceEnumProfitsharing Inv401kSourceEnum = "PROFITSHARING"
+	Inv401kSourceEnumRollover      Inv401kSourceEnum = "ROLLOVER"
+	Inv401kSourceEnumOthervest     Inv401kSourceEnum = "OTHERVEST"
+	Inv401kSourceEnumOthernonvest  Inv401kSourceEnum = "OTHERNONVEST"
+)
+
+// BooleanType is Y or N.
+type BooleanType string
+
+const (
+	BooleanTypeY BooleanType = "Y"
+	BooleanTypeN BooleanType = "N"
+)
+
+// SeverityEnum is the severity of a STATUS.
+type SeverityEnum string
+
+const (
+	SeverityEnumInfo  SeverityEnum = "INFO"
+	SeverityEnumWarn  SeverityEnum = "WARN"
+	SeverityEnumError SeverityEnum = "ERROR"
+)
+
+// BalanceTypeEnum is the type of the value of a BAL.
+type BalanceTypeEnum string
+
+const (
+	BalanceTypeEnumDollar  BalanceTypeEnum = "DOLLAR"
+	BalanceTypeEnumPercent BalanceTypeEnum = "PERCENT"
+	BalanceTypeEnumNumber  BalanceTypeEnum = "NUMBER"
+)
+
+// AccountEnum is the type of a bank account.
+type AccountEnum string
+
+const (
+	AccountEnumChecking   AccountEnum = "CHECKING"
+	AccountEnumSavings    AccountEnum = "SAVINGS"
+	AccountEnumMoneymrkt  AccountEnum = "MONEYMRKT"
+	AccountEnumCreditline AccountEnum = "CREDITLINE"
+	AccountEnumCd         AccountEnum = "CD"
+)
+
+// SubAccountEnum is the sub-account of a security or cash position.
+type SubAccountEnum string
+
+const (
+	SubAccountEnumCash   SubAccountEnum = "CASH"
+	SubAccountEnumMargin SubAccountEnum = "MARGIN"
+	SubAccountEnumShort  SubAccountEnum = "SHORT"
+	SubAccountEnumOther  SubAccountEnum = "OTHER"
+)
+
+// BuyTypeEnum is the BUYTYPE of a purchase.
+type BuyTypeEnum string
+
+const (
+	BuyTypeEnumBuy        BuyTypeEnum = "BUY"
+	BuyTypeEnumBuytocover BuyTypeEnum = "BUYTOCOVER"
+)
+
+// SellTypeEnum is the SELLTYPE of a sale.
+type SellTypeEnum string
+
+const (
+	SellTypeEnumSell      SellTypeEnum = "SELL"
+	SellTypeEnumSellshort SellTypeEnum = "SELLSHORT"
+)
+
+// IncomeTypeEnum is the INCOMETYPE of investment income.
+type IncomeTypeEnum string
+
+const (
+	IncomeTypeEnumCglong   IncomeTypeEnum = "CGLONG"
+	IncomeTypeEnumCgshort  IncomeTypeEnum = "CGSHORT"
+	IncomeTypeEnumDiv      IncomeTypeEnum = "DIV"
+	IncomeTypeEnumInterest IncomeTypeEnum = "INTEREST"
+	IncomeTypeEnumMisc     IncomeTypeEnum = "MISC"
+)
+
+// PositionTypeEnum is the POSTYPE of a position.
+type PositionTypeEnum string
+
+const (
+	PositionTypeEnumLong  PositionTypeEnum = "LONG"
+	PositionTypeEnumShort PositionTypeEnum = "SHORT"
+)
+
+// LoanAccountEnum is the type of a loan account.
+type LoanAccountEnum string
+
+const (
+	LoanAccountEnumAuto       LoanAccountEnum = "AUTO"
+	LoanAccountEnumConsumer   LoanAccountEnum = "CONSUMER"
+	LoanAccountEnumMortgage   LoanAccountEnum = "MORTGAGE"
+	LoanAccountEnumCommercial LoanAccountEnum = "COMMERCIAL"
+	LoanAccountEnumStudent    LoanAccountEnum = "STUDENT"
+	LoanAccountEnumMilitary   LoanAccountEnum = "MILITARY"
+	LoanAccountEnumSmb        LoanAccountEnum = "SMB"
+	LoanAccountEnumConstr     LoanAccountEnum = "CONSTR"
+	LoanAccountEnumHomeequity LoanAccountEnum = "HOMEEQUITY"
+)
+
+// Payee is the PAYEE of a transaction.
+type Payee struct {
+	Name       string `xml:"NAME,omitempty"`
+	Addr1      string `xml:"ADDR1,omitempty"`
+	Addr2      string `xml:"ADDR2,omitempty"`
+	Addr3      string `xml:"ADDR3,omitempty"`
+	City       string `xml:"CITY,omitempty"`
+	State      string `xml:"STATE,omitempty"`
+	Postalcode string `xml:"POSTALCODE,omitempty"`
+	Country    string `xml:"COUNTRY,omitempty"`
+	Phone      string `xml:"PHONE,omitempty"`
+}
+
+// StatementTransaction is a STMTTRN.
+type StatementTransaction struct {
+	Trntype       TransactionEnum      `xml:"TRNTYPE,omitempty"`
+	Dtposted      string               `xml:"DTPOSTED,omitempty"`
+	Dtuser        string               `xml:"DTUSER,omitempty"`
+	Dtavail       string               `xml:"DTAVAIL,omitempty"`
+	Trnamt        string               `xml:"TRNAMT,omitempty"`
+	Fitid         string               `xml:"FITID,omitempty"`
+	Correctfitid  string               `xml:"CORRECTFITID,omitempty"`
+	Correctaction CorrectiveActionEnum `xml:"CORRECTACTION,omitempty"`
+	Srvrtid       string               `xml:"SRVRTID,omitempty"`
+	Checknum      string               `xml:"CHECKNUM,omitempty"`
+	Refnum        string               `xml:"REFNUM,omitempty"`
+	Sic           int                  `xml:"SIC,omitempty"`
+	Payeeid       string               `xml:"PAYEEID,omitempty"`
+	Name          string               `xml:"NAME,omitempty"`
+	Payee         *Payee               `xml:"PAYEE,omitempty"`
+	Extdname      string               `xml:"EXTDNAME,omitempty"`
+	Bankacctto    *BankAccount         `xml:"BANKACCTTO,omitempty"`
+	Ccacctto      *CreditCardAccount   `xml:"CCACCTTO,omitempty"`
+	Memo          string               `xml:"MEMO,omitempty"`
+	Currency      *Currency            `xml:"CURRENCY,omitempty"`
+	Origcurrency  *Currency            `xml:"ORIGCURRENCY,omitempty"`
+	Inv401ksource Inv401kSourceEnum    `xml:"INV401KSOURCE,omitempty"`
+}
+
+// BankTransactionList is a BANKTRANLIST.
+type BankTransactionList struct {
+	Dtstart string                 `xml:"DTSTART,omitempty"`
+	Dtend   string                 `xml:"DTEND,omitempty"`
+	Stmttrn []StatementTransaction `xml:"STMTTRN,omitempty"`
+}
+
+// StatementResponse is the STMTRS of a bank statement.
+type StatementResponse struct {
+	Curdef       string               `xml:"CURDEF,omitempty"`
+	Bankacctfrom BankAccount          `xml:"BANKACCTFROM"`
+	Banktranlist *BankTransactionList `xml:"BANKTRANLIST,omitempty"`
+	Ledgerbal    Balance              `xml:"LEDGERBAL"`
+	Availbal     *Balance             `xml:"AVAILBAL,omitempty"`
+	Ballist      *BalanceList         `xml:"BALLIST,omitempty"`
+	Mktginfo     string               `xml:"MKTGINFO,omitempty"`
+}
+
+// StatementTransactionResponse is the STMTTRNRS wrapping a STMTRS.
+type StatementTransactionResponse struct {
+	Trnuid    string             `xml:"TRNUID,omitempty"`
+	Status    Status             `xml:"STATUS"`
+	Cltcookie string             `xml:"CLTCOOKIE,omitempty"`
+	Stmtrs    *StatementResponse `xml:"STMTRS,omitempty"`
+}
+
+// ClosingInformation is a CLOSING of a closing statement.
+type ClosingInformation struct {
+	Fitid        string    `xml:"FITID,omitempty"`
+	Dtopen       string    `xml:"DTOPEN,omitempty"`
+	Dtclose      string    `xml:"DTCLOSE,omitempty"`
+	Dtnext       string    `xml:"DTNEXT,omitempty"`
+	Balopen      string    `xml:"BALOPEN,omitempty"`
+	Balclose     string    `xml:"BALCLOSE,omitempty"`
+	Balmin       string    `xml:"BALMIN,omitempty"`
+	Depandcredit string    `xml:"DEPANDCREDIT,omitempty"`
+	Chkanddebit  string    `xml:"CHKANDDEBIT,omitempty"`
+	Totalfees    string    `xml:"TOTALFEES,omitempty"`
+	Totalint     string    `xml:"TOTALINT,omitempty"`
+	Dtpoststart  string    `xml:"DTPOSTSTART,omitempty"`
+	Dtpostend    string    `xml:"DTPOSTEND,omitempty"`
+	Mktginfo     string    `xml:"MKTGINFO,omitempty"`
+	Currency     *Currency `xml:"CURRENCY,omitempty"`
+	Origcurrency *Currency `xml:"ORIGCURRENCY,omitempty"`
+}
+
+// StatementEndResponse is the STMTENDRS of a closing statement.
+type StatementEndResponse struct {
+	Curdef       string               `xml:"CURDEF,omitempty"`
+	Bankacctfrom BankAccount          `xml:"BANKACCTFROM"`
+	Closing      []ClosingInformation `xml:"CLOSING,omitempty"`
+}
+
+// StatementEndTransactionResponse is the STMTENDTRNRS wrapping a STMTENDRS.
+type StatementEndTransactionResponse struct {
+	Trnuid    string                `xml:"TRNUID,omitempty"`
+	Status    Status                `xml:"STATUS"`
+	Cltcookie string                `xml:"CLTCOOKIE,omitempty"`
+	Stmtendrs *StatementEndResponse `xml:"STMTENDRS,omitempty"`
+}
+
+// BankResponseMessageSetV1 is a BANKMSGSRSV1.
+type BankResponseMessageSetV1 struct {
+	Stmttrnrs    []StatementTransactionResponse    `xml:"STMTTRNRS,omitempty"`
+	Stmtendtrnrs []StatementEndTransactionResponse `xml:"STMTENDTRNRS,omitempty"`
+}
+
+// CreditCardStatementResponse is the CCSTMTRS of a credit card statement.
+type CreditCardStatementResponse struct {
+	Curdef       string               `xml:"CURDEF,omitempty"`
+	Ccacctfrom   CreditCardAccount    `xml:"CCACCTFROM"`
+	Banktranlist *BankTransactionList `xml:"BANKTRANLIST,omitempty"`
+	Ledgerbal    Balance              `xml:"LEDGERBAL"`
+	Availbal     *Balance             `xml:"AVAILBAL,omitempty"`
+	Ballist      *BalanceList         `xml:"BALLIST,omitempty"`
+	Mktginfo     string               `xml:"MKTGINFO,omitempty"`
+}
+
+// CreditCardStatementTransactionResponse is the CCSTMTTRNRS wrapping a CCSTMTRS.
+type CreditCardStatementTransactionResponse struct {
+	Trnuid    string                       `xml:"TRNUID,omitempty"`
+	Status    Status                       `xml:"STATUS"`
+	Cltcookie string                       `xml:"CLTCOOKIE,omitempty"`
+	Ccstmtrs  *CreditCardStatementResponse `xml:"CCSTMTRS,omitempty"`
+}
+
+// CreditCardResponseMessageSetV1 is a CREDITCARDMSGSRSV1.
+type CreditCardResponseMessageSetV1 struct {
+	Ccstmttrnrs []CreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS,omitempty"`
+}
+
+// Status is the STATUS of a response.
+type Status struct {
+	Code     int          `xml:"CODE,omitempty"`
+	Severity SeverityEnum `xml:"SEVERITY,omitempty"`
+	Message  string       `xml:"MESSAGE,omitempty"`
+}
+
+// Currency is the CURRENCY or ORIGCURRENCY of an amount.
+type Currency struct {
+	Currate string `xml:"CURRATE,omitempty"`
+	Cursym  string `xml:"CURSYM,omitempty"`
+}
+
+// Balance is a LEDGERBAL or AVAILBAL.
+type Balance struct {
+	Balamt string `xml:"BALAMT,omitempty"`
+	Dtasof string `xml:"DTASOF,omitempty"`
+}
+
+// GenericBalance is a BAL of a BALLIST.
+type GenericBalance struct {
+	Name     string          `xml:"NAME,omitempty"`
+	Desc     string          `xml:"DESC,omitempty"`
+	Baltype  BalanceTypeEnum `xml:"BALTYPE,omitempty"`
+	Value    string          `xml:"VALUE,omitempty"`
+	Dtasof   string          `xml:"DTASOF,omitempty"`
+	Currency *Currency       `xml:"CURRENCY,omitempty"`
+}
+
+// BalanceList is a BALLIST.
+type BalanceList struct {
+	Bal []GenericBalance `xml:"BAL,omitempty"`
+}
+
+// BankAccount identifies a bank account, e.g. in BANKACCTFROM.
+type BankAccount struct {
+	Bankid   string      `xml:"BANKID,omitempty"`
+	Branchid string      `xml:"BRANCHID,omitempty"`
+	Acctid   string      `xml:"ACCTID,omitempty"`
+	Accttype AccountEnum `xml:"ACCTTYPE,omitempty"`
+	Acctkey  string      `xml:"ACCTKEY,omitempty"`
+}
+
+// CreditCardAccount identifies a credit card account, e.g. in CCACCTFROM.
+type CreditCardAccount struct {
+	Acctid  string `xml:"ACCTID,omitempty"`
+	Acctkey string `xml:"ACCTKEY,omitempty"`
+}
+
+// InvestmentAccount identifies an investment account, e.g. in INVACCTFROM.
+type InvestmentAccount struct {
+	Brokerid string `xml:"BROKERID,omitempty"`
+	Acctid   string `xml:"ACCTID,omitempty"`
+}
+
+// SecurityID is the SECID of a security.
+type SecurityID struct {
+	Uniqueid     string `xml:"UNIQUEID,omitempty"`
+	Uniqueidtype string `xml:"UNIQUEIDTYPE,omitempty"`
+}
+
+// InvestmentTransaction is the INVTRAN common to investment transactions.
+type InvestmentTransaction struct {
+	Fitid         string `xml:"FITID,omitempty"`
+	Srvrtid       string `xml:"SRVRTID,omitempty"`
+	Dttrade       string `xml:"DTTRADE,omitempty"`
+	Dtsettle      string `xml:"DTSETTLE,omitempty"`
+	Reversalfitid string `xml:"REVERSALFITID,omitempty"`
+	Memo          string `xml:"MEMO,omitempty"`
+}
+
+// InvestmentBankTransaction is an INVBANKTRAN.
+type InvestmentBankTransaction struct {
+	Stmttrn     StatementTransaction `xml:"STMTTRN"`
+	Subacctfund SubAccountEnum       `xml:"SUBACCTFUND,omitempty"`
+}
+
+// InvestmentBuy is the INVBUY common to purchases.
+type InvestmentBuy struct {
+	Invtran       InvestmentTransaction `xml:"INVTRAN"`
+	Secid         SecurityID            `xml:"SECID"`
+	Units         string                `xml:"UNITS,omitempty"`
+	Unitprice     string                `xml:"UNITPRICE,omitempty"`
+	Markup        string                `xml:"MARKUP,omitempty"`
+	Commission    string                `xml:"COMMISSION,omitempty"`
+	Taxes         string                `xml:"TAXES,omitempty"`
+	Fees          string                `xml:"FEES,omitempty"`
+	Load          string                `xml:"LOAD,omitempty"`
+	Total         string                `xml:"TOTAL,omitempty"`
+	Currency      *Currency             `xml:"CURRENCY,omitempty"`
+	Origcurrency  *Currency             `xml:"ORIGCURRENCY,omitempty"`
+	Subacctsec    SubAccountEnum        `xml:"SUBACCTSEC,omitempty"`
+	Subacctfund   SubAccountEnum        `xml:"SUBACCTFUND,omitempty"`
+	Inv401ksource Inv401kSourceEnum     `xml:"INV401KSOURCE,omitempty"`
+}
+
+// InvestmentSell is the INVSELL common to sales.
+type InvestmentSell struct {
+	Invtran       InvestmentTransaction `xml:"INVTRAN"`
+	Secid         SecurityID            `xml:"SECID"`
+	Units         string                `xml:"UNITS,omitempty"`
+	Unitprice     string                `xml:"UNITPRICE,omitempty"`
+	Markdown      string                `xml:"MARKDOWN,omitempty"`
+	Commission    string                `xml:"COMMISSION,omitempty"`
+	Taxes         string                `xml:"TAXES,omitempty"`
+	Fees          string                `xml:"FEES,omitempty"`
+	Load          string                `xml:"LOAD,omitempty"`
+	Withholding   string                `xml:"WITHHOLDING,omitempty"`
+	Taxexempt     BooleanType           `xml:"TAXEXEMPT,omitempty"`
+	Total         string                `xml:"TOTAL,omitempty"`
+	Gain          string                `xml:"GAIN,omitempty"`
+	Currency      *Currency             `xml:"CURRENCY,omitempty"`
+	Origcurrency  *Currency             `xml:"ORIGCURRENCY,omitempty"`
+	Subacctsec    SubAccountEnum        `xml:"SUBACCTSEC,omitempty"`
+	Subacctfund   SubAccountEnum        `xml:"SUBACCTFUND,omitempty"`
+	Inv401ksource Inv401kSourceEnum     `xml:"INV401KSOURCE,omitempty"`
+}
+
+// BuyStock is a BUYSTOCK.
+type BuyStock struct {
+	Invbuy  InvestmentBuy `xml:"INVBUY"`
+	Buytype BuyTypeEnum   `xml:"BUYTYPE,omitempty"`
+}
+
+// SellStock is a SELLSTOCK.
+type SellStock struct {
+	Invsell  InvestmentSell `xml:"INVSELL"`
+	Selltype SellTypeEnum   `xml:"SELLTYPE,omitempty"`
+}
+
+// Income is an INCOME.
+type Income struct {
+	Invtran       InvestmentTransaction `xml:"INVTRAN"`
+	Secid         SecurityID            `xml:"SECID"`
+	Incometype    IncomeTypeEnum        `xml:"INCOMETYPE,omitempty"`
+	Total         string                `xml:"TOTAL,omitempty"`
+	Subacctsec    SubAccountEnum        `xml:"SUBACCTSEC,omitempty"`
+	Subacctfund   SubAccountEnum        `xml:"SUBACCTFUND,omitempty"`
+	Taxexempt     BooleanType           `xml:"TAXEXEMPT,omitempty"`
+	Withholding   string                `xml:"WITHHOLDING,omitempty"`
+	Currency      *Currency             `xml:"CURRENCY,omitempty"`
+	Origcurrency  *Currency             `xml:"ORIGCURRENCY,omitempty"`
+	Inv401ksource Inv401kSourceEnum     `xml:"INV401KSOURCE,omitempty"`
+}
+
+// InvestmentTransactionList is an INVTRANLIST.
+type InvestmentTransactionList struct {
+	Dtstart     string                      `xml:"DTSTART,omitempty"`
+	Dtend       string                      `xml:"DTEND,omitempty"`
+	Buystock    []BuyStock                  `xml:"BUYSTOCK,omitempty"`
+	Sellstock   []SellStock                 `xml:"SELLSTOCK,omitempty"`
+	Income      []Income                    `xml:"INCOME,omitempty"`
+	Invbanktran []InvestmentBankTransaction `xml:"INVBANKTRAN,omitempty"`
+}
+
+// InvestmentPosition is the INVPOS common to positions.
+type InvestmentPosition struct {
+	Secid         SecurityID        `xml:"SECID"`
+	Heldinacct    SubAccountEnum    `xml:"HELDINACCT,omitempty"`
+	Postype       PositionTypeEnum  `xml:"POSTYPE,omitempty"`
+	Units         string            `xml:"UNITS,omitempty"`
+	Unitprice     string            `xml:"UNITPRICE,omitempty"`
+	Mktval        string            `xml:"MKTVAL,omitempty"`
+	Dtpriceasof   string            `xml:"DTPRICEASOF,omitempty"`
+	Currency      *Currency         `xml:"CURRENCY,omitempty"`
+	Origcurrency  *Currency         `xml:"ORIGCURRENCY,omitempty"`
+	Memo          string            `xml:"MEMO,omitempty"`
+	Inv401ksource Inv401kSourceEnum `xml:"INV401KSOURCE,omitempty"`
+}
+
+// StockPosition is a POSSTOCK.
+type StockPosition struct {
+	Invpos      InvestmentPosition `xml:"INVPOS"`
+	Unitsstreet string             `xml:"UNITSSTREET,omitempty"`
+	Unitsuser   string             `xml:"UNITSUSER,omitempty"`
+	Reinvdiv    BooleanType        `xml:"REINVDIV,omitempty"`
+}
+
+// MutualFundPosition is a POSMF.
+type MutualFundPosition struct {
+	Invpos      InvestmentPosition `xml:"INVPOS"`
+	Unitsstreet string             `xml:"UNITSSTREET,omitempty"`
+	Unitsuser   string             `xml:"UNITSUSER,omitempty"`
+	Reinvdiv    BooleanType        `xml:"REINVDIV,omitempty"`
+	Reinvcg     BooleanType        `xml:"REINVCG,omitempty"`
+}
+
+// InvestmentPositionList is an INVPOSLIST.
+type InvestmentPositionList struct {
+	Posstock []StockPosition      `xml:"POSSTOCK,omitempty"`
+	Posmf    []MutualFundPosition `xml:"POSMF,omitempty"`
+}
+
+// InvestmentBalance is an INVBAL.
+type InvestmentBalance struct {
+	Availcash     string       `xml:"AVAILCASH,omitempty"`
+	Marginbalance string       `xml:"MARGINBALANCE,omitempty"`
+	Shortbalance  string       `xml:"SHORTBALANCE,omitempty"`
+	Buypower      string       `xml:"BUYPOWER,omitempty"`
+	Ballist       *BalanceList `xml:"BALLIST,omitempty"`
+}
+
+// InvestmentStatementResponse is the INVSTMTRS of an investment statement.
+type InvestmentStatementResponse struct {
+	Dtasof      string                     `xml:"DTASOF,omitempty"`
+	Curdef      string                     `xml:"CURDEF,omitempty"`
+	Invacctfrom InvestmentAccount          `xml:"INVACCTFROM"`
+	Invtranlist *InvestmentTransactionList `xml:"INVTRANLIST,omitempty"`
+	Invposlist  *InvestmentPositionList    `xml:"INVPOSLIST,omitempty"`
+	Invbal      *InvestmentBalance         `xml:"INVBAL,omitempty"`
+	Mktginfo    string                     `xml:"MKTGINFO,omitempty"`
+}
+
+// InvestmentStatementTransactionResponse is the INVSTMTTRNRS wrapping an INVSTMTRS.
+type InvestmentStatementTransactionResponse struct {
+	Trnuid    string                       `xml:"TRNUID,omitempty"`
+	Status    Status                       `xml:"STATUS"`
+	Cltcookie string                       `xml:"CLTCOOKIE,omitempty"`
+	Invstmtrs *InvestmentStatementResponse `xml:"INVSTMTRS,omitempty"`
+}
+
+// InvestmentResponseMessageSetV1 is an INVSTMTMSGSRSV1.
+type InvestmentResponseMessageSetV1 struct {
+	Invstmttrnrs []InvestmentStatementTransactionResponse `xml:"INVSTMTTRNRS,omitempty"`
+}
+
+// LoanAccount identifies a loan account, e.g. in LOANACCTFROM.
+type LoanAccount struct {
+	Loanacctid   string          `xml:"LOANACCTID,omitempty"`
+	Loanaccttype LoanAccountEnum `xml:"LOANACCTTYPE,omitempty"`
+}
+
+// LoanTransactionAmount is the LOANTRNAMT splitting a loan transaction.
+type LoanTransactionAmount struct {
+	Prinamt string `xml:"PRINAMT,omitempty"`
+	Intamt  string `xml:"INTAMT,omitempty"`
+}
+
+// LoanStatementTransaction is a LOANSTMTTRN.
+type LoanStatementTransaction struct {
+	Trntype       TransactionEnum       `xml:"TRNTYPE,omitempty"`
+	Dtposted      string                `xml:"DTPOSTED,omitempty"`
+	Dtuser        string                `xml:"DTUSER,omitempty"`
+	Trnamt        string                `xml:"TRNAMT,omitempty"`
+	Loantrnamt    LoanTransactionAmount `xml:"LOANTRNAMT"`
+	Fitid         string                `xml:"FITID,omitempty"`
+	Correctfitid  string                `xml:"CORRECTFITID,omitempty"`
+	Correctaction CorrectiveActionEnum  `xml:"CORRECTACTION,omitempty"`
+	Srvrtid       string                `xml:"SRVRTID,omitempty"`
+	Checknum      string                `xml:"CHECKNUM,omitempty"`
+	Refnum        string                `xml:"REFNUM,omitempty"`
+	Payeeid       string                `xml:"PAYEEID,omitempty"`
+	Name          string                `xml:"NAME,omitempty"`
+	Payee         *Payee                `xml:"PAYEE,omitempty"`
+	Memo          string                `xml:"MEMO,omitempty"`
+	Currency      *Currency             `xml:"CURRENCY,omitempty"`
+	Origcurrency  *Currency             `xml:"ORIGCURRENCY,omitempty"`
+}
+
+// LoanTransactionList is a LOANTRANLIST.
+type LoanTransactionList struct {
+	Dtstart     string                     `xml:"DTSTART,omitempty"`
+	Dtend       string                     `xml:"DTEND,omitempty"`
+	Loanstmttrn []LoanStatementTransaction `xml:"LOANSTMTTRN,omitempty"`
+}
+
+// LoanStatementResponse is the LOANSTMTRS of a loan statement.
+type LoanStatementResponse struct {
+	Curdef       string               `xml:"CURDEF,omitempty"`
+	Loanacctfrom LoanAccount          `xml:"LOANACCTFROM"`
+	Loantranlist *LoanTransactionList `xml:"LOANTRANLIST,omitempty"`
+	Ballist      *BalanceList         `xml:"BALLIST,omitempty"`
+	Mktginfo     string               `xml:"MKTGINFO,omitempty"`
+}
+
+// LoanStatementTransactionResponse is the LOANSTMTTRNRS wrapping a LOANSTMTRS.
+type LoanStatementTransactionResponse struct {
+	Trnuid     string                 `xml:"TRNUID,omitempty"`
+	Status     Status                 `xml:"STATUS"`
+	Cltcookie  string                 `xml:"CLTCOOKIE,omitempty"`
+	Loanstmtrs *LoanStatementResponse `xml:"LOANSTMTRS,omitempty"`
+}
+
+// LoanResponseMessageSetV1 is a LOANMSGSRSV1.
+type LoanResponseMessageSetV1 struct {
+	Loanstmttrnrs []LoanStatementTransactionResponse `xml:"LOANSTMTTRNRS,omitempty"`
+}
+
+// FinancialInstitution identifies the institution in FI.
+type FinancialInstitution struct {
+	Org string `xml:"ORG,omitempty"`
+	Fid string `xml:"FID,omitempty"`
+}
+
+// SignonResponse is the SONRS of a signon response message set.
+type SignonResponse struct {
+	Status      Status                `xml:"STATUS"`
+	Dtserver    string                `xml:"DTSERVER,omitempty"`
+	Userkey     string                `xml:"USERKEY,omitempty"`
+	Tskeyexpire string                `xml:"TSKEYEXPIRE,omitempty"`
+	Language    string                `xml:"LANGUAGE,omitempty"`
+	Dtprofup    string                `xml:"DTPROFUP,omitempty"`
+	Dtacctup    string                `xml:"DTACCTUP,omitempty"`
+	Fi          *FinancialInstitution `xml:"FI,omitempty"`
+	Sesscookie  string                `xml:"SESSCOOKIE,omitempty"`
+	Accesskey   string                `xml:"ACCESSKEY,omitempty"`
+	Accesstoken string                `xml:"ACCESSTOKEN,omitempty"`
+}
+
+// SignonResponseMessageSetV1 is a SIGNONMSGSRSV1.
+type SignonResponseMessageSetV1 struct {
+	Sonrs SignonResponse `xml:"SONRS"`
+}
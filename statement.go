@@ -0,0 +1,170 @@
+package goofx
+
+import "github.com/rockstardevs/decimal"
+
+// Statement is implemented by the statements of every message set, to read them without
+// knowing their kind.
+type Statement interface {
+	// GetAccount returns the account the statement is for.
+	GetAccount() Account
+	// GetCurrency returns the default currency (CURDEF) of the statement.
+	GetCurrency() string
+	// GetPeriod returns the start and end dates of the transactions of the statement.
+	GetPeriod() (string, string)
+	// GetBalances returns the balances of the statement, the ones with a dedicated aggregate
+	// named after its tag, e.g. LEDGERBAL, followed by the BALLIST records.
+	GetBalances() BalanceList
+	// GetTransactions returns the transactions of the statement.
+	GetTransactions() []Transaction
+}
+
+var (
+	_ Statement = (*StatementResponseSet)(nil)
+	_ Statement = (*CreditCardStatementResponseSet)(nil)
+	_ Statement = (*InvestmentStatementResponseSet)(nil)
+	_ Statement = (*LoanStatementResponseSet)(nil)
+)
+
+// balances returns the given balances followed by the BALLIST records. Balances without a
+// date are left out, as they were not in the statement.
+func balances(list BalanceList, named ...NamedBalance) BalanceList {
+	result := make(BalanceList, 0, len(named)+len(list))
+	for _, b := range named {
+		if b.Date != "" {
+			result = append(result, b)
+		}
+	}
+	return append(result, list...)
+}
+
+// dollarBalance returns a NamedBalance for a balance with a dedicated aggregate.
+func dollarBalance(name, description string, value decimal.Decimal, date string) NamedBalance {
+	return NamedBalance{Name: name, Description: description, Type: BalanceDollar, Value: value, Date: date}
+}
+
+// optionalBalance is like dollarBalance for a balance that is nil if it was not in the
+// statement, which then has no date.
+func optionalBalance(name, description string, value *decimal.Decimal, date string) NamedBalance {
+	if value == nil {
+		return NamedBalance{Name: name}
+	}
+	return dollarBalance(name, description, *value, date)
+}
+
+// GetCurrency returns the default currency of the bank statement.
+func (s *StatementResponseSet) GetCurrency() string {
+	return s.Currency
+}
+
+// GetPeriod returns the start and end dates of the bank statement.
+func (s *StatementResponseSet) GetPeriod() (string, string) {
+	return s.StartDate, s.EndDate
+}
+
+// GetBalances returns the LEDGERBAL and AVAILBAL balances and the BALLIST records.
+func (s *StatementResponseSet) GetBalances() BalanceList {
+	return balances(s.Balances,
+		dollarBalance("LEDGERBAL", "Ledger balance", s.LedgerBalance.Amount, s.LedgerBalance.Date),
+		dollarBalance("AVAILBAL", "Available balance", s.AvailableBalance.Amount, s.AvailableBalance.Date))
+}
+
+// GetTransactions returns the transactions of the bank statement.
+func (s *StatementResponseSet) GetTransactions() []Transaction {
+	return s.Transactions
+}
+
+// GetCurrency returns the default currency of the credit card statement.
+func (s *CreditCardStatementResponseSet) GetCurrency() string {
+	return s.Currency
+}
+
+// GetPeriod returns the start and end dates of the credit card statement.
+func (s *CreditCardStatementResponseSet) GetPeriod() (string, string) {
+	return s.StartDate, s.EndDate
+}
+
+// GetBalances returns the LEDGERBAL and AVAILBAL balances and the BALLIST records.
+func (s *CreditCardStatementResponseSet) GetBalances() BalanceList {
+	return balances(s.Balances,
+		dollarBalance("LEDGERBAL", "Ledger balance", s.LedgerBalance.Amount, s.LedgerBalance.Date),
+		dollarBalance("AVAILBAL", "Available balance", s.AvailableBalance.Amount, s.AvailableBalance.Date))
+}
+
+// GetTransactions returns the transactions of the credit card statement.
+func (s *CreditCardStatementResponseSet) GetTransactions() []Transaction {
+	return s.Transactions
+}
+
+// GetCurrency returns the default currency of the investment statement.
+func (s *InvestmentStatementResponseSet) GetCurrency() string {
+	return s.Currency
+}
+
+// GetPeriod returns the start and end dates of the INVTRANLIST.
+func (s *InvestmentStatementResponseSet) GetPeriod() (string, string) {
+	return s.Transactions.StartDate, s.Transactions.EndDate
+}
+
+// GetBalances returns the AVAILCASH, MARGINBALANCE, SHORTBALANCE and BUYPOWER balances of the
+// INVBAL that are in the statement, as of the statement date, and its BALLIST records.
+func (s *InvestmentStatementResponseSet) GetBalances() BalanceList {
+	b := s.Balance
+	return balances(b.Balances,
+		optionalBalance("AVAILCASH", "Available cash", b.AvailableCash, s.Date),
+		optionalBalance("MARGINBALANCE", "Margin balance", b.Margin, s.Date),
+		optionalBalance("SHORTBALANCE", "Short balance", b.Short, s.Date),
+		optionalBalance("BUYPOWER", "Buying power", b.BuyingPower, s.Date))
+}
+
+// GetTransactions returns the cash transactions (INVBANKTRAN) of the investment statement.
+func (s *InvestmentStatementResponseSet) GetTransactions() []Transaction {
+	txns := make([]Transaction, 0, len(s.Transactions.Transactions))
+	for _, t := range s.Transactions.Transactions {
+		txns = append(txns, t.Transaction)
+	}
+	return txns
+}
+
+// GetCurrency returns the default currency of the loan statement.
+func (s *LoanStatementResponseSet) GetCurrency() string {
+	return s.Currency
+}
+
+// GetPeriod returns the start and end dates of the loan statement.
+func (s *LoanStatementResponseSet) GetPeriod() (string, string) {
+	return s.StartDate, s.EndDate
+}
+
+// GetBalances returns the BALLIST records of the loan statement.
+func (s *LoanStatementResponseSet) GetBalances() BalanceList {
+	return balances(s.Balances)
+}
+
+// GetTransactions returns the transactions of the loan statement, without the split of their
+// amounts.
+func (s *LoanStatementResponseSet) GetTransactions() []Transaction {
+	txns := make([]Transaction, 0, len(s.Transactions))
+	for _, t := range s.Transactions {
+		txns = append(txns, t.Transaction)
+	}
+	return txns
+}
+
+// Statements returns the statements of all message sets in the document, bank statements
+// first followed by credit card, investment and loan statements.
+func (d *Document) Statements() []Statement {
+	statements := make([]Statement, 0, len(d.BRMS)+len(d.CCRMS)+len(d.IRMS)+len(d.LRMS))
+	for i := range d.BRMS {
+		statements = append(statements, &d.BRMS[i].TRS.RS)
+	}
+	for i := range d.CCRMS {
+		statements = append(statements, &d.CCRMS[i].TRS.RS)
+	}
+	for i := range d.IRMS {
+		statements = append(statements, &d.IRMS[i].TRS.RS)
+	}
+	for i := range d.LRMS {
+		statements = append(statements, &d.LRMS[i].TRS.RS)
+	}
+	return statements
+}
@@ -0,0 +1,99 @@
+package goofx_test
+
+import (
+	"strings"
+
+	"github.com/rockstardevs/decimal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("Document.Statements()", func() {
+		data := "<OFX>" +
+			"<BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD<BANKACCTFROM><BANKID>456<ACCTID>789" +
+			"</BANKACCTFROM><BANKTRANLIST><DTSTART>20190101<DTEND>20190131<STMTTRN><TRNTYPE>DEBIT" +
+			"<DTPOSTED>20190115<TRNAMT>-5.00<FITID>b1</STMTTRN></BANKTRANLIST><LEDGERBAL><BALAMT>100.00" +
+			"<DTASOF>20190131</LEDGERBAL><BALLIST><BAL><NAME>LIMIT<DESC>Overdraft limit<BALTYPE>DOLLAR" +
+			"<VALUE>500</BAL></BALLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1>" +
+			"<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><CURDEF>CAD<CCACCTFROM><ACCTID>4111</CCACCTFROM>" +
+			"<BANKTRANLIST><DTSTART>20190201<DTEND>20190228</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS>" +
+			"</CREDITCARDMSGSRSV1>" +
+			"<INVSTMTMSGSRSV1><INVSTMTTRNRS><INVSTMTRS><DTASOF>20190131<CURDEF>USD<INVACCTFROM>" +
+			"<BROKERID>broker.com<ACCTID>X1</INVACCTFROM><INVTRANLIST><DTSTART>20190101<DTEND>20190131" +
+			"<INVBANKTRAN><STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20190110<TRNAMT>10.00<FITID>i1</STMTTRN>" +
+			"<SUBACCTFUND>CASH</INVBANKTRAN></INVTRANLIST><INVBAL><AVAILCASH>10.00<MARGINBALANCE>0" +
+			"<SHORTBALANCE>0</INVBAL></INVSTMTRS></INVSTMTTRNRS></INVSTMTMSGSRSV1>" +
+			"<LOANMSGSRSV1><LOANSTMTTRNRS><LOANSTMTRS><CURDEF>USD<LOANACCTFROM><LOANACCTID>L1" +
+			"</LOANACCTFROM><LOANTRANLIST><DTSTART>20190101<DTEND>20190131<LOANSTMTTRN><TRNTYPE>PAYMENT" +
+			"<DTPOSTED>20190105<TRNAMT>-1000.00<FITID>l1</LOANSTMTTRN></LOANTRANLIST></LOANSTMTRS>" +
+			"</LOANSTMTTRNRS></LOANMSGSRSV1>" +
+			"</OFX>"
+
+		var statements []goofx.Statement
+		BeforeEach(func() {
+			d, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			statements = d.Statements()
+		})
+
+		It("should return the statements of every message set in order", func() {
+			Expect(statements).To(HaveLen(4))
+			kinds := make([]goofx.AccountKind, 0)
+			for _, s := range statements {
+				kinds = append(kinds, s.GetAccount().Kind)
+			}
+			Expect(kinds).To(Equal([]goofx.AccountKind{goofx.AccountBank, goofx.AccountCreditCard,
+				goofx.AccountInvestment, goofx.AccountLoan}))
+		})
+		It("should expose the currency and period", func() {
+			Expect(statements[1].GetCurrency()).To(Equal("CAD"))
+			start, end := statements[1].GetPeriod()
+			Expect(start).To(Equal("20190201"))
+			Expect(end).To(Equal("20190228"))
+			start, end = statements[2].GetPeriod()
+			Expect(start).To(Equal("20190101"))
+			Expect(end).To(Equal("20190131"))
+		})
+		It("should expose the transactions", func() {
+			fitIDs := make([]string, 0)
+			for _, s := range statements {
+				for _, t := range s.GetTransactions() {
+					fitIDs = append(fitIDs, t.FitID)
+				}
+			}
+			Expect(fitIDs).To(Equal([]string{"b1", "i1", "l1"}))
+		})
+		It("should expose the balances", func() {
+			names := func(l goofx.BalanceList) []string {
+				result := make([]string, 0)
+				for _, b := range l {
+					result = append(result, b.Name)
+				}
+				return result
+			}
+			bank := statements[0].GetBalances()
+			Expect(names(bank)).To(Equal([]string{"LEDGERBAL", "LIMIT"}))
+			Expect(bank[0].Value.String()).To(Equal("100"))
+			Expect(bank[0].Date).To(Equal("20190131"))
+			Expect(statements[1].GetBalances()).To(BeEmpty())
+			inv := statements[2].GetBalances()
+			Expect(names(inv)).To(Equal([]string{"AVAILCASH", "MARGINBALANCE", "SHORTBALANCE"}))
+			cash, found := inv.Value("AVAILCASH")
+			Expect(found).To(BeTrue())
+			Expect(cash.String()).To(Equal("10"))
+			Expect(statements[3].GetBalances()).To(BeEmpty())
+		})
+		It("should only expose the investment balances in the statement", func() {
+			s := goofx.InvestmentStatementResponseSet{Date: "20190131"}
+			Expect(s.GetBalances()).To(BeEmpty())
+			power := decimal.RequireFromString("25.00")
+			s.Balance.BuyingPower = &power
+			Expect(s.GetBalances()).To(Equal(goofx.BalanceList{{Name: "BUYPOWER", Description: "Buying power",
+				Type: goofx.BalanceDollar, Value: power, Date: "20190131"}}))
+		})
+	})
+})
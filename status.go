@@ -0,0 +1,365 @@
+package goofx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatusCode is the CODE of an OFX STATUS aggregate.
+type StatusCode int
+
+// Status codes defined by the OFX spec.
+const (
+	StatusSuccess                    StatusCode = 0
+	StatusClientUpToDate             StatusCode = 1
+	StatusGeneralError               StatusCode = 2000
+	StatusInvalidAccount             StatusCode = 2001
+	StatusGeneralAccountError        StatusCode = 2002
+	StatusAccountNotFound            StatusCode = 2003
+	StatusAccountClosed              StatusCode = 2004
+	StatusAccountNotAuthorized       StatusCode = 2005
+	StatusSourceAccountNotFound      StatusCode = 2006
+	StatusSourceAccountClosed        StatusCode = 2007
+	StatusSourceAccountNotAuthorized StatusCode = 2008
+	StatusDestAccountNotFound        StatusCode = 2009
+	StatusDestAccountClosed          StatusCode = 2010
+	StatusDestAccountNotAuthorized   StatusCode = 2011
+	StatusInvalidAmount              StatusCode = 2012
+	StatusDateTooSoon                StatusCode = 2014
+	StatusDateTooFarInFuture         StatusCode = 2015
+	StatusAlreadyCommitted           StatusCode = 2016
+	StatusAlreadyCanceled            StatusCode = 2017
+	StatusUnknownServerID            StatusCode = 2018
+	StatusDuplicateRequest           StatusCode = 2019
+	StatusInvalidDate                StatusCode = 2020
+	StatusUnsupportedVersion         StatusCode = 2021
+	StatusInvalidTAN                 StatusCode = 2022
+	StatusUnknownFitID               StatusCode = 2023
+	StatusBranchIDMissing            StatusCode = 2025
+	StatusBankNameMismatch           StatusCode = 2026
+	StatusInvalidDateRange           StatusCode = 2027
+	StatusRequestedElementUnknown    StatusCode = 2028
+	StatusMFAChallengeRequired       StatusCode = 3000
+	StatusMFAChallengeInvalid        StatusCode = 3001
+	StatusRejectIfMissingInvalid     StatusCode = 6500
+	StatusEmbeddedOutOfDate          StatusCode = 6501
+	StatusEmbeddedTokenOutOfDate     StatusCode = 6502
+	StatusStopCheckInProcess         StatusCode = 10000
+	StatusTooManyChecks              StatusCode = 10500
+	StatusInvalidPayee               StatusCode = 10501
+	StatusInvalidPayeeAddress        StatusCode = 10502
+	StatusInvalidPayeeAccount        StatusCode = 10503
+	StatusInsufficientFunds          StatusCode = 10504
+	StatusCannotModifyElement        StatusCode = 10505
+	StatusCannotModifySource         StatusCode = 10506
+	StatusCannotModifyDestination    StatusCode = 10507
+	StatusInvalidFrequency           StatusCode = 10508
+	StatusModelAlreadyCanceled       StatusCode = 10509
+	StatusInvalidPayeeID             StatusCode = 10510
+	StatusInvalidPayeeCity           StatusCode = 10511
+	StatusInvalidPayeeState          StatusCode = 10512
+	StatusInvalidPayeePostalCode     StatusCode = 10513
+	StatusAlreadyProcessed           StatusCode = 10514
+	StatusPayeeNotModifiable         StatusCode = 10515
+	StatusWireBeneficiaryInvalid     StatusCode = 10516
+	StatusInvalidPayeeName           StatusCode = 10517
+	StatusUnknownModelID             StatusCode = 10518
+	StatusInvalidPayeeListID         StatusCode = 10519
+	StatusTableTypeNotFound          StatusCode = 10600
+	StatusInvTranNotSupported        StatusCode = 12250
+	StatusInvPosNotSupported         StatusCode = 12251
+	StatusInvPosDateNotAvailable     StatusCode = 12252
+	StatusInvOpenOrderNotSupported   StatusCode = 12253
+	StatusInvBalanceNotSupported     StatusCode = 12254
+	Status401kNotAvailable           StatusCode = 12255
+	StatusSecurityNotFound           StatusCode = 12500
+	StatusPasswordSentOutOfBand      StatusCode = 13000
+	StatusUnableToEnroll             StatusCode = 13500
+	StatusAlreadyEnrolled            StatusCode = 13501
+	StatusInvalidService             StatusCode = 13502
+	StatusCannotChangeUserInfo       StatusCode = 13503
+	StatusFIMissingOrInvalid         StatusCode = 13504
+	Status1099NotAvailable           StatusCode = 14500
+	Status1099NotAvailableForUser    StatusCode = 14501
+	StatusW2NotAvailable             StatusCode = 14600
+	StatusW2NotAvailableForUser      StatusCode = 14601
+	Status1098NotAvailable           StatusCode = 14700
+	Status1098NotAvailableForUser    StatusCode = 14701
+	StatusMustChangePassword         StatusCode = 15000
+	StatusSignonInvalid              StatusCode = 15500
+	StatusAccountInUse               StatusCode = 15501
+	StatusPasswordLockout            StatusCode = 15502
+	StatusCouldNotChangePassword     StatusCode = 15503
+	StatusCouldNotProvideRandomData  StatusCode = 15504
+	StatusCountrySystemNotAvailable  StatusCode = 15505
+	StatusEmptySignonNotSupported    StatusCode = 15506
+	StatusSignonInvalidWithoutPin    StatusCode = 15507
+	StatusTransactionNotAuthorized   StatusCode = 15508
+	StatusClientUIDError             StatusCode = 15510
+	StatusMFAError                   StatusCode = 15511
+	StatusAuthTokenRequired          StatusCode = 15512
+	StatusAuthTokenInvalid           StatusCode = 15513
+	StatusHTMLNotAllowed             StatusCode = 16500
+	StatusUnknownMailTo              StatusCode = 16501
+	StatusInvalidURL                 StatusCode = 16502
+	StatusUnableToGetURL             StatusCode = 16503
+)
+
+// statusMessages holds the spec description of each known status code.
+var statusMessages = map[StatusCode]string{
+	StatusSuccess:                    "success",
+	StatusClientUpToDate:             "client is up-to-date",
+	StatusGeneralError:               "general error",
+	StatusInvalidAccount:             "invalid account",
+	StatusGeneralAccountError:        "general account error",
+	StatusAccountNotFound:            "account not found",
+	StatusAccountClosed:              "account closed",
+	StatusAccountNotAuthorized:       "account not authorized",
+	StatusSourceAccountNotFound:      "source account not found",
+	StatusSourceAccountClosed:        "source account closed",
+	StatusSourceAccountNotAuthorized: "source account not authorized",
+	StatusDestAccountNotFound:        "destination account not found",
+	StatusDestAccountClosed:          "destination account closed",
+	StatusDestAccountNotAuthorized:   "destination account not authorized",
+	StatusInvalidAmount:              "invalid amount",
+	StatusDateTooSoon:                "date too soon",
+	StatusDateTooFarInFuture:         "date too far in future",
+	StatusAlreadyCommitted:           "transaction already committed",
+	StatusAlreadyCanceled:            "already canceled",
+	StatusUnknownServerID:            "unknown server id",
+	StatusDuplicateRequest:           "duplicate request",
+	StatusInvalidDate:                "invalid date",
+	StatusUnsupportedVersion:         "unsupported version",
+	StatusInvalidTAN:                 "invalid TAN",
+	StatusUnknownFitID:               "unknown FITID",
+	StatusBranchIDMissing:            "branch id missing",
+	StatusBankNameMismatch:           "bank name doesn't match bank id",
+	StatusInvalidDateRange:           "invalid date range",
+	StatusRequestedElementUnknown:    "requested element unknown",
+	StatusMFAChallengeRequired:       "MFA challenge authentication required",
+	StatusMFAChallengeInvalid:        "MFA challenge information is invalid",
+	StatusRejectIfMissingInvalid:     "REJECTIFMISSING invalid without TOKEN",
+	StatusEmbeddedOutOfDate:          "embedded transactions in request failed to process: out of date",
+	StatusEmbeddedTokenOutOfDate:     "unable to process embedded transaction due to out-of-date TOKEN",
+	StatusStopCheckInProcess:         "stop check in process",
+	StatusTooManyChecks:              "too many checks to process",
+	StatusInvalidPayee:               "invalid payee",
+	StatusInvalidPayeeAddress:        "invalid payee address",
+	StatusInvalidPayeeAccount:        "invalid payee account number",
+	StatusInsufficientFunds:          "insufficient funds",
+	StatusCannotModifyElement:        "cannot modify element",
+	StatusCannotModifySource:         "cannot modify source account",
+	StatusCannotModifyDestination:    "cannot modify destination account",
+	StatusInvalidFrequency:           "invalid frequency",
+	StatusModelAlreadyCanceled:       "model already canceled",
+	StatusInvalidPayeeID:             "invalid payee id",
+	StatusInvalidPayeeCity:           "invalid payee city",
+	StatusInvalidPayeeState:          "invalid payee state",
+	StatusInvalidPayeePostalCode:     "invalid payee postal code",
+	StatusAlreadyProcessed:           "transaction already processed",
+	StatusPayeeNotModifiable:         "payee not modifiable by client",
+	StatusWireBeneficiaryInvalid:     "wire beneficiary invalid",
+	StatusInvalidPayeeName:           "invalid payee name",
+	StatusUnknownModelID:             "unknown model id",
+	StatusInvalidPayeeListID:         "invalid payee list id",
+	StatusTableTypeNotFound:          "table type not found",
+	StatusInvTranNotSupported:        "investment transaction download not supported",
+	StatusInvPosNotSupported:         "investment position download not supported",
+	StatusInvPosDateNotAvailable:     "investment positions for specified date not available",
+	StatusInvOpenOrderNotSupported:   "investment open order download not supported",
+	StatusInvBalanceNotSupported:     "investment balances download not supported",
+	Status401kNotAvailable:           "401(k) not available for this account",
+	StatusSecurityNotFound:           "one or more securities not found",
+	StatusPasswordSentOutOfBand:      "user id & password will be sent out-of-band",
+	StatusUnableToEnroll:             "unable to enroll user",
+	StatusAlreadyEnrolled:            "user already enrolled",
+	StatusInvalidService:             "invalid service",
+	StatusCannotChangeUserInfo:       "cannot change user information",
+	StatusFIMissingOrInvalid:         "FI missing or invalid in SONRQ",
+	Status1099NotAvailable:           "1099 forms not available",
+	Status1099NotAvailableForUser:    "1099 forms not available for user id",
+	StatusW2NotAvailable:             "W2 forms not available",
+	StatusW2NotAvailableForUser:      "W2 forms not available for user id",
+	Status1098NotAvailable:           "1098 forms not available",
+	Status1098NotAvailableForUser:    "1098 forms not available for user id",
+	StatusMustChangePassword:         "must change USERPASS",
+	StatusSignonInvalid:              "signon invalid",
+	StatusAccountInUse:               "customer account already in use",
+	StatusPasswordLockout:            "USERPASS lockout",
+	StatusCouldNotChangePassword:     "could not change USERPASS",
+	StatusCouldNotProvideRandomData:  "could not provide random data",
+	StatusCountrySystemNotAvailable:  "country system not available",
+	StatusEmptySignonNotSupported:    "empty signon not supported",
+	StatusSignonInvalidWithoutPin:    "signon invalid without supporting pin change request",
+	StatusTransactionNotAuthorized:   "transaction not authorized",
+	StatusClientUIDError:             "CLIENTUID error",
+	StatusMFAError:                   "MFA error",
+	StatusAuthTokenRequired:          "AUTHTOKEN required",
+	StatusAuthTokenInvalid:           "AUTHTOKEN invalid",
+	StatusHTMLNotAllowed:             "HTML not allowed",
+	StatusUnknownMailTo:              "unknown mail To:",
+	StatusInvalidURL:                 "invalid URL",
+	StatusUnableToGetURL:             "unable to get URL",
+}
+
+// String returns the spec description of the status code.
+func (c StatusCode) String() string {
+	if m, ok := statusMessages[c]; ok {
+		return m
+	}
+	return fmt.Sprintf("status %d", int(c))
+}
+
+// IsKnown returns true if the code is defined by the OFX spec.
+func (c StatusCode) IsKnown() bool {
+	_, ok := statusMessages[c]
+	return ok
+}
+
+// IsSuccess returns true for the codes reporting a successful request.
+func (c StatusCode) IsSuccess() bool {
+	return c == StatusSuccess || c == StatusClientUpToDate
+}
+
+// IsAccountError returns true for the codes reporting an invalid, unknown or unusable account.
+func (c StatusCode) IsAccountError() bool {
+	return c >= StatusInvalidAccount && c <= StatusDestAccountNotAuthorized
+}
+
+// IsCredentialError returns true for the codes reporting rejected or expired sign on
+// credentials, including the ones requiring multi-factor authentication.
+func (c StatusCode) IsCredentialError() bool {
+	switch c {
+	case StatusMFAChallengeRequired, StatusMFAChallengeInvalid:
+		return true
+	}
+	return c >= StatusMustChangePassword && c <= StatusAuthTokenInvalid
+}
+
+// Severity is the SEVERITY of an OFX STATUS aggregate.
+type Severity string
+
+// Severities defined by the OFX spec.
+const (
+	SeverityInfo  Severity = "INFO"
+	SeverityWarn  Severity = "WARN"
+	SeverityError Severity = "ERROR"
+)
+
+// Status is an OFX STATUS aggregate.
+type Status struct {
+	Code     StatusCode `xml:"CODE"`
+	Severity Severity   `xml:"SEVERITY"`
+	Message  string     `xml:"MESSAGE,omitempty"`
+	Extra    Extra      `xml:",any"`
+}
+
+// Err returns a *StatusError for statuses other than success, or nil.
+func (s Status) Err() error {
+	if s.Code.IsSuccess() && s.Severity != SeverityError {
+		return nil
+	}
+	return &StatusError{Code: s.Code, Severity: s.Severity, Message: s.Message}
+}
+
+// StatusError is returned for an OFX response whose STATUS is not success. It matches any
+// *StatusError with the same code with errors.Is, e.g.
+// errors.Is(err, &goofx.StatusError{Code: goofx.StatusSignonInvalid}).
+type StatusError struct {
+	Code     StatusCode
+	Severity Severity
+	Message  string
+}
+
+// Error returns the code, its description and the server message, if any.
+func (e *StatusError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "error - ofx status %d (%s)", int(e.Code), e.Code.String())
+	if e.Severity != "" {
+		fmt.Fprintf(&b, " severity %s", e.Severity)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	return b.String()
+}
+
+// Is reports whether target is a *StatusError with the same code as this error.
+func (e *StatusError) Is(target error) bool {
+	t, ok := target.(*StatusError)
+	return ok && t.Code == e.Code
+}
+
+// Status returns the STATUS of the sign on response.
+func (r *SignOnResponse) Status() Status {
+	return Status{Code: r.Code, Severity: r.Severity, Message: r.Message}
+}
+
+// Err returns a *StatusError if the sign on failed, or nil.
+func (r *SignOnResponse) Err() error {
+	return r.Status().Err()
+}
+
+// Status returns the STATUS of the transaction response.
+func (t *StatementTransactionResponseSet) Status() Status {
+	return Status{Code: t.Code, Severity: t.Severity, Message: t.Message}
+}
+
+// Err returns a *StatusError if the statement request failed, or nil.
+func (t *StatementTransactionResponseSet) Err() error {
+	return t.Status().Err()
+}
+
+// Status returns the STATUS of the transaction response.
+func (t *CreditCardTransactionResponseSet) Status() Status {
+	return Status{Code: t.Code, Severity: t.Severity, Message: t.Message}
+}
+
+// Err returns a *StatusError if the statement request failed, or nil.
+func (t *CreditCardTransactionResponseSet) Err() error {
+	return t.Status().Err()
+}
+
+// Status returns the STATUS of the transaction response.
+func (t *InvestmentTransactionResponseSet) Status() Status {
+	return Status{Code: t.Code, Severity: t.Severity, Message: t.Message}
+}
+
+// Err returns a *StatusError if the statement request failed, or nil.
+func (t *InvestmentTransactionResponseSet) Err() error {
+	return t.Status().Err()
+}
+
+// Status returns the STATUS of the transaction response.
+func (t *LoanTransactionResponseSet) Status() Status {
+	return Status{Code: t.Code, Severity: t.Severity, Message: t.Message}
+}
+
+// Err returns a *StatusError if the statement request failed, or nil.
+func (t *LoanTransactionResponseSet) Err() error {
+	return t.Status().Err()
+}
+
+// Err returns the error of the sign on response if it failed, else the error of the first
+// failed statement response, or nil.
+func (d *Document) Err() error {
+	errs := []func() error{d.Response.Err}
+	for i := range d.BRMS {
+		errs = append(errs, d.BRMS[i].TRS.Err)
+	}
+	for i := range d.CCRMS {
+		errs = append(errs, d.CCRMS[i].TRS.Err)
+	}
+	for i := range d.IRMS {
+		errs = append(errs, d.IRMS[i].TRS.Err)
+	}
+	for i := range d.LRMS {
+		errs = append(errs, d.LRMS[i].TRS.Err)
+	}
+	for _, e := range errs {
+		if err := e(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package goofx_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+var _ = Describe("goofx", func() {
+	Describe("NewDocumentFromXML() with a failed status", func() {
+		It("should parse the typed status and return it from Err()", func() {
+			r := strings.NewReader("<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>15500<SEVERITY>ERROR" +
+				"<MESSAGE>Invalid password</STATUS><DTSERVER>20190131200000<LANGUAGE>ENG</SONRS>" +
+				"</SIGNONMSGSRSV1></OFX>")
+			d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Response.Code).To(Equal(goofx.StatusSignonInvalid))
+			Expect(d.Response.Severity).To(Equal(goofx.SeverityError))
+			Expect(d.Response.Message).To(Equal("Invalid password"))
+			err = d.Err()
+			Expect(errors.Is(err, &goofx.StatusError{Code: goofx.StatusSignonInvalid})).To(BeTrue())
+			Expect(err).To(MatchError("error - ofx status 15500 (signon invalid) severity ERROR: Invalid password"))
+		})
+		It("should return the error of a failed statement response", func() {
+			r := strings.NewReader("<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS>" +
+				"</SONRS></SIGNONMSGSRSV1><BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STATUS><CODE>2003" +
+				"<SEVERITY>ERROR</STATUS></STMTTRNRS></BANKMSGSRSV1></OFX>")
+			d, err := goofx.NewDocumentFromXML(r, goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Response.Err()).To(BeNil())
+			err = d.Err()
+			var serr *goofx.StatusError
+			Expect(errors.As(err, &serr)).To(BeTrue())
+			Expect(serr.Code).To(Equal(goofx.StatusAccountNotFound))
+			Expect(serr.Code.IsAccountError()).To(BeTrue())
+			Expect(errors.Is(err, &goofx.StatusError{Code: goofx.StatusInvalidAccount})).To(BeFalse())
+		})
+	})
+	Describe("Status", func() {
+		DescribeTable("Err()", func(s goofx.Status, expected string) {
+			err := s.Err()
+			if expected == "" {
+				Expect(err).To(BeNil())
+				return
+			}
+			Expect(err).To(MatchError(expected))
+		},
+			Entry("success", goofx.Status{Code: goofx.StatusSuccess, Severity: goofx.SeverityInfo}, ""),
+			Entry("up to date", goofx.Status{Code: goofx.StatusClientUpToDate, Severity: goofx.SeverityInfo}, ""),
+			Entry("general error", goofx.Status{Code: goofx.StatusGeneralError, Severity: goofx.SeverityError},
+				"error - ofx status 2000 (general error) severity ERROR"),
+			Entry("warning", goofx.Status{Code: goofx.StatusInvPosNotSupported, Severity: goofx.SeverityWarn},
+				"error - ofx status 12251 (investment position download not supported) severity WARN"),
+			Entry("unknown code", goofx.Status{Code: 9999}, "error - ofx status 9999 (status 9999)"),
+		)
+	})
+	Describe("StatusCode", func() {
+		It("should format as its description", func() {
+			Expect(fmt.Sprint(goofx.StatusSuccess)).To(Equal("success"))
+			Expect(fmt.Sprintf("%v", goofx.StatusCode(9999))).To(Equal("status 9999"))
+		})
+		DescribeTable("categories", func(c goofx.StatusCode, known, success, account, credential bool) {
+			Expect(c.IsKnown()).To(Equal(known))
+			Expect(c.IsSuccess()).To(Equal(success))
+			Expect(c.IsAccountError()).To(Equal(account))
+			Expect(c.IsCredentialError()).To(Equal(credential))
+		},
+			Entry("success", goofx.StatusSuccess, true, true, false, false),
+			Entry("duplicate request", goofx.StatusDuplicateRequest, true, false, false, false),
+			Entry("account not found", goofx.StatusAccountNotFound, true, false, true, false),
+			Entry("must change password", goofx.StatusMustChangePassword, true, false, false, true),
+			Entry("password lockout", goofx.StatusPasswordLockout, true, false, false, true),
+			Entry("mfa challenge", goofx.StatusMFAChallengeRequired, true, false, false, true),
+			Entry("unknown", goofx.StatusCode(42), false, false, false, false),
+		)
+	})
+})
@@ -0,0 +1,238 @@
+package goofx
+
+import (
+	"context"
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	xmlNameType         = reflect.TypeOf(xml.Name{})
+	timeType            = reflect.TypeOf(time.Time{})
+	xmlUnmarshalerType  = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Unmarshal parses the given file as NewDocumentFromXML does, but decodes it into v, which
+// must be a pointer to a struct for the OFX aggregate. See Node.Decode for the supported tags.
+func Unmarshal(reader io.Reader, cleaner Cleaner, v interface{}, opts ...ParseOption) error {
+	return UnmarshalContext(context.Background(), reader, cleaner, v, opts...)
+}
+
+// UnmarshalContext is like Unmarshal but stops parsing and returns ctx.Err() once the given
+// context is done.
+func UnmarshalContext(ctx context.Context, reader io.Reader, cleaner Cleaner, v interface{}, opts ...ParseOption) error {
+	root, err := NewTreeContext(ctx, reader, cleaner, opts...)
+	if err != nil {
+		return err
+	}
+	return root.Decode(v)
+}
+
+// decodeNode decodes the given node into v.
+func decodeNode(n *Node, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeNode(n, v.Elem())
+	}
+	if v.Type() == timeType {
+		if n.Text == "" {
+			return nil
+		}
+		t, err := ParseDate(n.Text, nil)
+		if err != nil {
+			return fmt.Errorf("error - %s: %w", n.Name, err)
+		}
+		v.Set(reflect.ValueOf(*t))
+		return nil
+	}
+	if v.CanAddr() {
+		switch p := v.Addr(); {
+		case p.Type().Implements(xmlUnmarshalerType):
+			return xml.NewTokenDecoder(newNodeTokenReader(n)).Decode(p.Interface())
+		case p.Type().Implements(textUnmarshalerType):
+			if n.Text == "" {
+				return nil
+			}
+			return p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(n.Text))
+		}
+	}
+	if v.Kind() == reflect.Struct {
+		return decodeStruct(n, v)
+	}
+	return decodeText(n.Name, n.Text, v)
+}
+
+// decodeText decodes the given text of the element with the given name into v.
+func decodeText(name, text string, v reflect.Value) error {
+	if text == "" {
+		return nil
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(text, 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(text, 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(text, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	case reflect.Bool:
+		switch strings.ToUpper(text) {
+		case "Y":
+			v.SetBool(true)
+		case "N":
+			v.SetBool(false)
+		default:
+			var b bool
+			if b, err = strconv.ParseBool(text); err == nil {
+				v.SetBool(b)
+			}
+		}
+	default:
+		return fmt.Errorf("error - %s: can not decode into %s", name, v.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("error - %s: %w", name, err)
+	}
+	return nil
+}
+
+// decodeStruct decodes the children of the given node into the fields of struct v.
+func decodeStruct(n *Node, v reflect.Value) error {
+	mapped := make(map[string]bool)
+	var any []reflect.Value
+	if err := decodeFields(n, v, mapped, &any); err != nil {
+		return err
+	}
+	for _, field := range any {
+		for _, child := range n.Children {
+			if mapped[child.Name] {
+				continue
+			}
+			if err := decodeInto(child, field); err != nil {
+				return err
+			}
+			if field.Kind() != reflect.Slice {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// decodeFields decodes the children of the given node into the fields of struct v, including
+// those of embedded structs. It records the names of mapped children, and returns the ",any"
+// fields to decode unmapped children into.
+func decodeFields(n *Node, v reflect.Value, mapped map[string]bool, any *[]reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, field := t.Field(i), v.Field(i)
+		tag, found := f.Tag.Lookup("ofx")
+		if !found {
+			tag = f.Tag.Get("xml")
+		}
+		name, flags := tag, ""
+		if j := strings.IndexByte(tag, ','); j != -1 {
+			name, flags = tag[:j], tag[j+1:]
+		}
+		switch {
+		case f.PkgPath != "" && !f.Anonymous, name == "-":
+			continue
+		case f.Name == "XMLName" && f.Type == xmlNameType:
+			field.Set(reflect.ValueOf(xml.Name{Local: n.Name}))
+			continue
+		case f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct:
+			if err := decodeFields(n, field, mapped, any); err != nil {
+				return err
+			}
+			continue
+		case f.PkgPath != "":
+			continue
+		}
+		switch {
+		case hasFlag(flags, "any"):
+			*any = append(*any, field)
+			continue
+		case hasFlag(flags, "chardata"):
+			if err := decodeText(n.Name, n.Text, field); err != nil {
+				return err
+			}
+			continue
+		case hasFlag(flags, "attr"), hasFlag(flags, "innerxml"), hasFlag(flags, "comment"):
+			// OFX has no attributes or comments.
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		path := strings.Split(name, ">")
+		for j := range path {
+			path[j] = NormalizeTag(strings.TrimSpace(path[j]))
+		}
+		mapped[path[0]] = true
+		for _, match := range findPath(n, path) {
+			if err := decodeInto(match, field); err != nil {
+				return err
+			}
+			if field.Kind() != reflect.Slice || field.Type().Elem().Kind() == reflect.Uint8 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// decodeInto decodes the given node into field, appending a new element if it is a slice.
+func decodeInto(n *Node, field reflect.Value) error {
+	if field.Kind() != reflect.Slice || field.Type().Elem().Kind() == reflect.Uint8 {
+		return decodeNode(n, field)
+	}
+	elem := reflect.New(field.Type().Elem()).Elem()
+	if err := decodeNode(n, elem); err != nil {
+		return err
+	}
+	field.Set(reflect.Append(field, elem))
+	return nil
+}
+
+// findPath returns the descendants of the given node at the given path of names.
+func findPath(n *Node, path []string) []*Node {
+	nodes := []*Node{n}
+	for _, name := range path {
+		var next []*Node
+		for _, node := range nodes {
+			next = append(next, node.ChildrenNamed(name)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// hasFlag returns true if the given comma separated flags contain flag.
+func hasFlag(flags, flag string) bool {
+	for _, f := range strings.Split(flags, ",") {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package goofx_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rockstardevs/decimal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/rockstardevs/goofx"
+)
+
+type projectionStatus struct {
+	Code     int    `ofx:"CODE"`
+	Severity string `ofx:"SEVERITY"`
+}
+
+type projectionTransaction struct {
+	Type   string          `ofx:"trntype"`
+	Posted time.Time       `ofx:"DTPOSTED"`
+	Amount decimal.Decimal `ofx:"TRNAMT"`
+	Memo   *string         `ofx:"MEMO"`
+	Extra  goofx.Extra     `ofx:",any"`
+}
+
+type projection struct {
+	Status       projectionStatus        `ofx:"SIGNONMSGSRSV1>SONRS>STATUS"`
+	Org          string                  `xml:"SIGNONMSGSRSV1>SONRS>FI>ORG"`
+	Primary      bool                    `ofx:"SIGNONMSGSRSV1>SONRS>INTU.PRIMARY"`
+	Transactions []projectionTransaction `ofx:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+	FitIDs       []string                `ofx:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN>FITID"`
+	Ignored      string                  `ofx:"-" xml:"SIGNONMSGSRSV1>SONRS>LANGUAGE"`
+}
+
+type embeddedProjection struct {
+	projectionStatus
+	Language string `ofx:"LANGUAGE"`
+}
+
+var _ = Describe("goofx", func() {
+	Describe("Unmarshal()", func() {
+		data := "<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>2000<SEVERITY>ERROR</STATUS><LANGUAGE>ENG" +
+			"<FI><ORG>Test Bank</FI><INTU.PRIMARY>Y</SONRS></SIGNONMSGSRSV1>" +
+			"<BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>" +
+			"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20190119<TRNAMT>-20.96<FITID>1<CHECKNUM>1001</STMTTRN>" +
+			"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20190120<TRNAMT>5<FITID>2<MEMO>Refund</STMTTRN>" +
+			"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+
+		It("should decode into a caller defined struct", func() {
+			var p projection
+			Expect(goofx.Unmarshal(strings.NewReader(data), goofx.NewCleaner(), &p)).To(Succeed())
+			Expect(p.Org).To(Equal("Test Bank"))
+			Expect(p.Ignored).To(BeEmpty())
+			Expect(p.Primary).To(BeTrue())
+			Expect(p.Transactions).To(HaveLen(2))
+			Expect(p.Transactions[0].Type).To(Equal("DEBIT"))
+			Expect(p.Transactions[0].Posted).To(Equal(time.Date(2019, 1, 19, 0, 0, 0, 0, time.FixedZone("UTC", 0))))
+			Expect(p.Transactions[0].Amount.String()).To(Equal("-20.96"))
+			Expect(p.Transactions[0].Memo).To(BeNil())
+			Expect(p.Transactions[0].Extra).To(HaveLen(2))
+			Expect(p.Transactions[0].Extra[1].Name()).To(Equal("CHECKNUM"))
+			Expect(*p.Transactions[1].Memo).To(Equal("Refund"))
+			Expect(p.FitIDs).To(Equal([]string{"1", "2"}))
+		})
+		It("should decode tagged and embedded structs", func() {
+			var p projection
+			Expect(goofx.Unmarshal(strings.NewReader(data), goofx.NewCleaner(), &p)).To(Succeed())
+			Expect(p.Status.Code).To(Equal(2000))
+			Expect(p.Status.Severity).To(Equal("ERROR"))
+
+			var e struct {
+				Response embeddedProjection `ofx:"SIGNONMSGSRSV1>SONRS"`
+			}
+			Expect(goofx.Unmarshal(strings.NewReader(data), goofx.NewCleaner(), &e)).To(Succeed())
+			Expect(e.Response.Language).To(Equal("ENG"))
+			Expect(e.Response.Code).To(BeZero())
+		})
+		It("should decode into a Document", func() {
+			var d goofx.Document
+			Expect(goofx.Unmarshal(strings.NewReader(data), goofx.NewCleaner(), &d)).To(Succeed())
+			expected, err := goofx.NewDocumentFromXML(strings.NewReader(data), goofx.NewCleaner())
+			Expect(err).To(BeNil())
+			Expect(d.Response).To(Equal(expected.Response))
+			Expect(d.BRMS).To(Equal(expected.BRMS))
+		})
+		It("should fail for values that can not be decoded", func() {
+			var p struct {
+				Org int `ofx:"SIGNONMSGSRSV1>SONRS>FI>ORG"`
+			}
+			Expect(goofx.Unmarshal(strings.NewReader(data), goofx.NewCleaner(), &p)).NotTo(Succeed())
+		})
+		It("should fail for non-pointer values", func() {
+			var p projection
+			Expect(goofx.Unmarshal(strings.NewReader(data), goofx.NewCleaner(), p)).NotTo(Succeed())
+		})
+	})
+})
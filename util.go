@@ -3,6 +3,7 @@ package goofx
 import (
 	"bytes"
 	"encoding/xml"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/golang/glog"
@@ -114,3 +115,140 @@ func writeElementFromName(name xml.Name, data string, buff *bytes.Buffer) {
 	buff.WriteString(data)
 	writeEndTag(name, buff)
 }
+
+// escapeStrayMarkup escapes each '<' in the given data that starts tag-like text which can
+// not be a tag at that point, so the decoder reads it as literal char data. This is the case
+// for a '<' that doesn't start a complete tag, e.g. <NAME>A<B LLC or <MEMO>1 < 2, and for
+// unknown tags after the data of an element, e.g. <MEMO>Transfer <savings>. Unknown end tags
+// that are a misspelling of the open element, e.g. <NAME>Foo</NAME1>, are left for the
+// cleaner. Tags defined by any version of the spec are never escaped, elements are those of
+// the given schema.
+func escapeStrayMarkup(data []byte, schema *Schema) []byte {
+	var (
+		result      bytes.Buffer
+		openElement string // Set when the last known tag was an element start tag.
+		hasData     bool   // Set when the open element has data so far.
+	)
+	result.Grow(len(data))
+	for i := 0; i < len(data); {
+		j := bytes.IndexByte(data[i:], '<')
+		if j == -1 {
+			result.Write(data[i:])
+			break
+		}
+		if len(bytes.TrimSpace(data[i:i+j])) > 0 {
+			hasData = true
+		}
+		result.Write(data[i : i+j])
+		i += j
+
+		length, name, isEnd := scanTag(data[i:])
+		if length == 0 || (openElement != "" && hasData && name != "" && !isSpecTag(name) &&
+			!(isEnd && editDistance(NormalizeTag(name), openElement) <= maxEditDistance)) {
+			glog.V(3).Infof("escaping stray markup at offset %d", i)
+			result.Write(escLt)
+			hasData = true
+			i++
+			continue
+		}
+		if name != "" {
+			openElement, hasData = "", false
+			if !isEnd && schema.IsElement(name) {
+				openElement = NormalizeTag(name)
+			}
+		}
+		result.Write(data[i : i+length])
+		i += length
+	}
+	return result.Bytes()
+}
+
+// isSpecTag returns true if the given tag is defined by any version of the spec, or is an
+// extension tag.
+func isSpecTag(tag string) bool {
+	_, found := Since(tag)
+	return found || strings.Contains(tag, ".")
+}
+
+// scanTag scans the tag at the start of the given data, which must start with '<'.
+// It returns the length of the tag, its name and whether it is an end tag. The name is empty
+// for processing instructions, comments and other markup declarations. The length is 0 if
+// the data doesn't start with a complete tag.
+func scanTag(data []byte) (length int, name string, isEnd bool) {
+	for _, d := range [][2]string{{"<!--", "-->"}, {"<![CDATA[", "]]>"}, {"<?", "?>"}, {"<!", ">"}} {
+		if bytes.HasPrefix(data, []byte(d[0])) {
+			end := bytes.Index(data[len(d[0]):], []byte(d[1]))
+			if end == -1 {
+				return 0, "", false
+			}
+			return len(d[0]) + end + len(d[1]), "", false
+		}
+	}
+
+	i := 1
+	if i < len(data) && data[i] == '/' {
+		isEnd = true
+		i++
+	}
+	start := i
+	for i < len(data) && isNameByte(data[i], i == start) {
+		i++
+	}
+	if i == start {
+		return 0, "", false
+	}
+	name = string(data[start:i])
+	// Skip over any attributes till the end of the tag, a tag can not contain another '<'.
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '>':
+			return i + 1, name, isEnd
+		case '<':
+			return 0, "", false
+		}
+	}
+	return 0, "", false
+}
+
+// isNameByte returns true if the given byte can be part of a tag name.
+func isNameByte(b byte, first bool) bool {
+	switch {
+	case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b == '_':
+		return true
+	case b >= '0' && b <= '9', b == '.', b == '-', b == ':':
+		return !first
+	}
+	return false
+}
+
+// editDistance returns the Levenshtein distance between the given strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// min3 returns the smallest of the given ints.
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}